@@ -0,0 +1,79 @@
+package qstring
+
+import "strings"
+
+var (
+	defaultBoolTrue  = []string{"1", "true"}
+	defaultBoolFalse = []string{"0", "false"}
+)
+
+// WithBoolValues sets the representations of bool accepted by Decoder.
+// The default is "1" and "true" for true, "0" and "false" for false.
+//
+// The field with the tag option "bool=<true>|<false>" (e.g. `qstring:"enabled,bool=yes|no"`)
+// also accepts the representations of the option, and is encoded with them.
+// The representations of the option must not be empty.
+func WithBoolValues(truthy, falsy []string) Option {
+	return func(c *config) {
+		c.boolTrue = truthy
+		c.boolFalse = falsy
+	}
+}
+
+// WithBoolCaseInsensitive makes Decoder compare the representations of bool case-insensitively.
+func WithBoolCaseInsensitive() Option {
+	return func(c *config) {
+		c.boolCaseInsensitive = true
+	}
+}
+
+// WithPresenceAsTrue makes Decoder decode the key without value (e.g. "?verbose") as true.
+func WithPresenceAsTrue() Option {
+	return func(c *config) {
+		c.presenceAsTrue = true
+	}
+}
+
+// parseBool returns the bool value of s.
+// The second return value reports whether s is a representation of bool.
+func (c *config) parseBool(s string, opt tagOption) (bool, bool) {
+	if s == "" && c.presenceAsTrue {
+		return true, true
+	}
+
+	truthy, falsy := c.boolTrue, c.boolFalse
+	if truthy == nil && falsy == nil {
+		truthy, falsy = defaultBoolTrue, defaultBoolFalse
+	}
+
+	if opt.boolTrue != "" && c.matchBool(s, []string{opt.boolTrue}) || c.matchBool(s, truthy) {
+		return true, true
+	}
+	if opt.boolFalse != "" && c.matchBool(s, []string{opt.boolFalse}) || c.matchBool(s, falsy) {
+		return false, true
+	}
+	return false, false
+}
+
+func (c *config) matchBool(s string, tokens []string) bool {
+	for _, t := range tokens {
+		if s == t || (c.boolCaseInsensitive && strings.EqualFold(s, t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatBool returns the representation of b for the field
+func formatBool(b bool, opt tagOption) string {
+	if opt.boolTrue != "" || opt.boolFalse != "" {
+		if b {
+			return opt.boolTrue
+		}
+		return opt.boolFalse
+	}
+	if b {
+		return "true"
+	}
+	return "false"
+}
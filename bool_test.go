@@ -0,0 +1,76 @@
+package qstring_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestDecoder_bool(t *testing.T) {
+	type s struct {
+		Flag    bool  `qstring:"flag"`
+		FlagP   *bool `qstring:"flag_p"`
+		Enabled bool  `qstring:"enabled,bool=yes|no"`
+	}
+
+	testCases := []struct {
+		name     string
+		opts     []qstring.Option
+		q        string
+		err      error
+		expected s
+	}{
+		{name: "default", q: "flag=true&flag_p=0", expected: s{Flag: true, FlagP: boolP(false)}},
+		{name: "default rejects on", q: "flag=on", err: fmt.Errorf(`"on" can not be assign to bool`)},
+		{name: "default is case sensitive", q: "flag=TRUE", err: fmt.Errorf(`"TRUE" can not be assign to bool`)},
+		{name: "tag option", q: "enabled=yes", expected: s{Enabled: true}},
+		{name: "tag option keeps default", q: "enabled=1", expected: s{Enabled: true}},
+		{name: "custom values", opts: []qstring.Option{qstring.WithBoolValues([]string{"on", "yes", "t"}, []string{"off", "no", "f"})},
+			q: "flag=on&flag_p=f", expected: s{Flag: true, FlagP: boolP(false)}},
+		{name: "custom values replace default", opts: []qstring.Option{qstring.WithBoolValues([]string{"on"}, []string{"off"})},
+			q: "flag=true", err: fmt.Errorf(`"true" can not be assign to bool`)},
+		{name: "case insensitive", opts: []qstring.Option{qstring.WithBoolCaseInsensitive()},
+			q: "flag=TRUE&flag_p=False&enabled=YES", expected: s{Flag: true, FlagP: boolP(false), Enabled: true}},
+		{name: "presence", opts: []qstring.Option{qstring.WithPresenceAsTrue()},
+			q: "flag&flag_p=", expected: s{Flag: true, FlagP: boolP(true)}},
+		{name: "no presence", q: "flag", err: fmt.Errorf(`"" can not be assign to bool`)},
+		{name: "tri-state absent", q: "flag=1", expected: s{Flag: true, FlagP: nil}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := s{}
+			err := qstring.NewDecoder(tc.opts...).Decode(tc.q, &v)
+			assertResult(t, "Decode()", v, err, tc.expected, tc.err)
+		})
+	}
+}
+
+func TestEncode_bool(t *testing.T) {
+	type s struct {
+		A bool  `qstring:"a,bool=1|0"`
+		B bool  `qstring:"b,bool=on|off"`
+		C *bool `qstring:"c,bool=yes|no"`
+		D bool  `qstring:"d"`
+	}
+
+	runEncodeTest(t, []encodeCase{
+		{name: "true", q: s{A: true, B: true, C: boolP(true), D: true}, expected: "a=1&b=on&c=yes&d=true"},
+		{name: "false", q: s{C: boolP(false)}, expected: "a=0&b=off&c=no&d=false"},
+	})
+
+	t.Run("empty representation", func(t *testing.T) {
+		type checkbox struct {
+			Checked bool `qstring:"checked,bool=on|"`
+		}
+		_, err := qstring.Encode(checkbox{})
+		if err == nil || err.Error() != `invalid tag option "bool=on|" of qstring_test.checkbox.Checked` {
+			t.Errorf("Encode() error = %v, want the invalid tag option", err)
+		}
+		err = qstring.NewDecoder(qstring.WithPresenceAsTrue()).Decode("checked=", &checkbox{})
+		if err == nil || err.Error() != `invalid tag option "bool=on|" of qstring_test.checkbox.Checked` {
+			t.Errorf("Decode() error = %v, want the invalid tag option", err)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package qstring
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"reflect"
+	"strings"
+)
+
+// bytesFormat is the format of []byte and [N]byte in the query string
+type bytesFormat int
+
+const (
+	// bytesBase64URL is base64url without padding, the default format
+	bytesBase64URL bytesFormat = iota
+	// bytesBase64Std is the standard base64 with padding, tag option "base64std"
+	bytesBase64Std
+	// bytesHex is the hexadecimal, tag option "hex"
+	bytesHex
+)
+
+func (f bytesFormat) encode(b []byte) string {
+	switch f {
+	case bytesBase64Std:
+		return base64.StdEncoding.EncodeToString(b)
+	case bytesHex:
+		return hex.EncodeToString(b)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (f bytesFormat) decode(s string) ([]byte, error) {
+	switch f {
+	case bytesBase64Std:
+		return base64.StdEncoding.DecodeString(s)
+	case bytesHex:
+		return hex.DecodeString(s)
+	}
+	// accept the padded form too
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// isBytesType reports whether the type is []byte or [N]byte
+func isBytesType(rt reflect.Type) bool {
+	switch rt.Kind() {
+	case reflect.Slice, reflect.Array:
+		return rt.Elem().Kind() == reflect.Uint8
+	}
+	return false
+}
+
+// bytesOf returns the bytes of []byte or [N]byte
+func bytesOf(rv reflect.Value) []byte {
+	if rv.Kind() == reflect.Slice {
+		return rv.Bytes()
+	}
+	b := make([]byte, rv.Len())
+	for i := range b {
+		b[i] = byte(rv.Index(i).Uint())
+	}
+	return b
+}
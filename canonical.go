@@ -0,0 +1,64 @@
+package qstring
+
+import (
+	"sort"
+	"strings"
+)
+
+// Canonicalize returns the canonical form of the query string for request signing.
+//
+// The keys and values are percent-encoded by RFC 3986
+// (the unreserved characters are left as is, "%20" is used for a space, hex digits are uppercase),
+// and the parameters are sorted in byte order of the encoded key and then the encoded value.
+func Canonicalize(query string) (string, error) {
+	d := decoder{query: strings.TrimPrefix(query, que)}
+	params, err := d.parseQuery()
+	if err != nil {
+		return "", err
+	}
+	return canonicalizeParams(params), nil
+}
+
+// CanonicalEncode returns the canonical form of the encoded value.
+//
+// The argument supports the same types as Encode.
+// See Canonicalize for the canonical form.
+func CanonicalEncode(v interface{}) (string, error) {
+	return canonicalEncode(config{}, v)
+}
+
+func canonicalEncode(c config, v interface{}) (string, error) {
+	e := encoder{config: c}
+	values, err := e.values(v)
+	if err != nil {
+		return "", err
+	}
+
+	params := make([]queryParam, 0, len(values))
+	for k, vs := range values {
+		for _, v := range vs {
+			params = append(params, queryParam{key: k, value: v})
+		}
+	}
+	return canonicalizeParams(params), nil
+}
+
+func canonicalizeParams(params []queryParam) string {
+	escaped := make([]queryParam, 0, len(params))
+	for _, p := range params {
+		escaped = append(escaped, queryParam{key: escapeRFC3986(p.key), value: escapeRFC3986(p.value)})
+	}
+
+	sort.Slice(escaped, func(i, j int) bool {
+		if escaped[i].key != escaped[j].key {
+			return escaped[i].key < escaped[j].key
+		}
+		return escaped[i].value < escaped[j].value
+	})
+
+	pairs := make([]string, 0, len(escaped))
+	for _, p := range escaped {
+		pairs = append(pairs, p.key+"="+p.value)
+	}
+	return strings.Join(pairs, "&")
+}
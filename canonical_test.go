@@ -0,0 +1,60 @@
+package qstring_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestCanonicalize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		q        string
+		err      error
+		expected string
+	}{
+		{name: "sort by key", q: "b=2&a=1&c=3", expected: "a=1&b=2&c=3"},
+		{name: "sort by value", q: "a=2&a=10&a=1", expected: "a=1&a=10&a=2"},
+		{name: "space", q: "a=x+y&b=x%20y", expected: "a=x%20y&b=x%20y"},
+		{name: "reserved characters", q: "k%5Ba%5D=%2F%3F%2a", expected: "k%5Ba%5D=%2F%3F%2A"},
+		{name: "unreserved characters", q: "a=%41-._~", expected: "a=A-._~"},
+		{name: "multibyte", q: "a=あ", expected: "a=%E3%81%82"},
+		{name: "byte order", q: "a=1&B=2&_=3", expected: "B=2&_=3&a=1"},
+		{name: "leading question", q: "?b=&a", expected: "a=&b="},
+		{name: "empty", q: "", expected: ""},
+		{name: "invalid escape", q: "a=%zz", err: errors.New(`invalid URL escape "%zz"`)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := qstring.Canonicalize(tc.q)
+			assertResult(t, "Canonicalize()", actual, err, tc.expected, tc.err)
+		})
+	}
+}
+
+func TestCanonicalEncode(t *testing.T) {
+	type s struct {
+		Name string   `qstring:"name"`
+		Tags []string `qstring:"tags"`
+	}
+
+	testCases := []struct {
+		name     string
+		v        interface{}
+		err      error
+		expected string
+	}{
+		{name: "struct", v: s{Name: "a b", Tags: []string{"y", "x"}}, expected: "name=a%20b&tags%5B0%5D=y&tags%5B1%5D=x"},
+		{name: "map", v: qstring.Q{"b": "1", "a": qstring.Q{"c": "*"}}, expected: "a%5Bc%5D=%2A&b=1"},
+		{name: "unsupported", v: 1, err: errors.New("int is not supported")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := qstring.CanonicalEncode(tc.v)
+			assertResult(t, "CanonicalEncode()", actual, err, tc.expected, tc.err)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package qstring
 
 import (
+	"errors"
 	"net/url"
 	"reflect"
 	"sort"
@@ -9,7 +10,15 @@ import (
 )
 
 type decoder struct {
-	query string
+	config config
+	query  string
+	// values is used instead of query if it is not nil
+	values url.Values
+	// collectErrors continues decoding the struct fields after an error
+	// and returns all errors as FieldErrors
+	collectErrors bool
+	// inlining is the structs being decoded with their inline fields
+	inlining map[reflect.Type]bool
 }
 
 func (d *decoder) decode(v interface{}) error {
@@ -29,6 +38,9 @@ func (d *decoder) decode(v interface{}) error {
 	case reflect.Map:
 		return d.decodeMap(rv)
 	case reflect.Struct:
+		if rv.Type() == orderedQType {
+			return d.decodeOrderedMap(rv)
+		}
 		return d.decodeStruct(rv)
 	}
 
@@ -36,7 +48,12 @@ func (d *decoder) decode(v interface{}) error {
 }
 
 func (d *decoder) decodeString(rv reflect.Value) error {
-	q, err := url.QueryUnescape(d.query)
+	query := d.query
+	if d.values != nil {
+		query = d.values.Encode()
+	}
+
+	q, err := url.QueryUnescape(query)
 	if err == nil {
 		rv.SetString(q)
 	}
@@ -90,38 +107,124 @@ func (d *decoder) decodeSlice(rv reflect.Value) error {
 	return nil
 }
 
+// queryParam is a key-value pair of the query string
+type queryParam struct {
+	key   string
+	value string
+}
+
+// parseQuery parses the query string like url.ParseQuery,
+// but keeps the order in which the parameters appear.
+func (d *decoder) parseQuery() ([]queryParam, error) {
+	lc := newLimitChecker(d.config.limits)
+	if d.values != nil {
+		return d.valuesToParams(lc)
+	}
+
+	query := d.query
+	if err := lc.checkTotal(len(query)); err != nil {
+		return nil, err
+	}
+	params := make([]queryParam, 0, lc.capacity(strings.Count(query, "&")+1))
+
+	for query != "" {
+		key := query
+		if i := strings.Index(key, "&"); i >= 0 {
+			key, query = key[:i], key[i+1:]
+		} else {
+			query = ""
+		}
+		if strings.Contains(key, ";") {
+			return nil, errors.New("invalid semicolon separator in query")
+		}
+		if key == "" {
+			continue
+		}
+
+		value := ""
+		if i := strings.Index(key, "="); i >= 0 {
+			key, value = key[:i], key[i+1:]
+		}
+
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := lc.check(key, value); err != nil {
+			return nil, err
+		}
+		params = append(params, queryParam{key: key, value: value})
+	}
+
+	return params, nil
+}
+
+// valuesToParams converts url.Values to the parameters sorted by key
+func (d *decoder) valuesToParams(lc *limitChecker) ([]queryParam, error) {
+	keys := make([]string, 0, len(d.values))
+	total := 0
+	for k, vs := range d.values {
+		keys = append(keys, k)
+		for _, v := range vs {
+			// the length of "k=v&"
+			total += len(k) + len(v) + 2
+		}
+	}
+	if err := lc.checkTotal(total - 1); err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+
+	params := make([]queryParam, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range d.values[k] {
+			if err := lc.check(k, v); err != nil {
+				return nil, err
+			}
+			params = append(params, queryParam{key: k, value: v})
+		}
+	}
+	return params, nil
+}
+
 func (d *decoder) createIntermediateStruct() (urlValueMap, error) {
-	urlValues, err := url.ParseQuery(d.query)
+	params, err := d.parseQuery()
 	if err != nil {
 		return nil, err
 	}
 
 	valueMap := make(urlValueMap)
 
-	for key, values := range urlValues {
+	for i, p := range params {
 		// convert `key[a][b]` to `[]string{"key", "a", "b"}`
-		splitKeys := strings.Split(key, "[")
+		splitKeys := strings.Split(p.key, "[")
 		for i, v := range splitKeys {
 			splitKeys[i] = strings.TrimSuffix(v, "]")
 		}
 
 		k := splitKeys[0]
 		if _, ok := valueMap[k]; ok {
-			valueMap[k] = d.toUrlValue(valueMap[k], splitKeys, values)
+			valueMap[k] = d.toUrlValue(valueMap[k], splitKeys, k, p.value, i)
 			continue
 		}
-		valueMap[k] = d.toUrlValue(urlValue{}, splitKeys, values)
+		valueMap[k] = d.toUrlValue(urlValue{order: i}, splitKeys, k, p.value, i)
 	}
 
 	return d.conpact(valueMap), nil
 }
 
-func (d *decoder) toUrlValue(uv urlValue, keys []string, values []string) urlValue {
+// toUrlValue adds the value to uv, path is the key of uv in bracket form (e.g. "key[a][b]")
+func (d *decoder) toUrlValue(uv urlValue, keys []string, path, value string, order int) urlValue {
 	key := keys[0]
 	uv.key = key
+	uv.path = path
 
 	if len(keys) == 1 {
-		uv.values = append(uv.values, values...)
+		uv.values = append(uv.values, value)
 		uv.isString = true
 		return uv
 	}
@@ -131,12 +234,13 @@ func (d *decoder) toUrlValue(uv urlValue, keys []string, values []string) urlVal
 	}
 
 	nextKey := keys[1]
+	nextPath := path + "[" + nextKey + "]"
 	if _, ok := uv.child[nextKey]; ok {
-		uv.child[nextKey] = d.toUrlValue(uv.child[nextKey], keys[1:], values)
+		uv.child[nextKey] = d.toUrlValue(uv.child[nextKey], keys[1:], nextPath, value, order)
 		return uv
 	}
 
-	uv.child[nextKey] = d.toUrlValue(urlValue{}, keys[1:], values)
+	uv.child[nextKey] = d.toUrlValue(urlValue{order: order}, keys[1:], nextPath, value, order)
 	return uv
 }
 
@@ -155,6 +259,9 @@ func (d *decoder) conpact(valueMap urlValueMap) urlValueMap {
 		// simple array value
 		if arrVals, ok := d.toArray(uv.child); ok {
 			uv.values = arrVals
+			if isIndexed(uv.child) {
+				uv.indexed = uv.child
+			}
 			uv.child = nil
 			newMap[uv.key] = uv
 			continue
@@ -168,6 +275,16 @@ func (d *decoder) conpact(valueMap urlValueMap) urlValueMap {
 	return newMap
 }
 
+// isIndexed reports whether all the keys are array indexes
+func isIndexed(valueMap urlValueMap) bool {
+	for k := range valueMap {
+		if i, err := strconv.Atoi(k); err != nil || i < 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func (d *decoder) toArray(valueMap urlValueMap) ([]string, bool) {
 	tmp := make([]urlValue, 0, len(valueMap))
 
@@ -191,7 +308,7 @@ func (d *decoder) toArray(valueMap urlValueMap) ([]string, bool) {
 	}
 
 	sort.Slice(tmp, func(i, j int) bool {
-		return tmp[i].key < tmp[j].key
+		return lessKey(tmp[i].key, tmp[j].key)
 	})
 
 	ret := make([]string, 0, len(valueMap))
@@ -25,49 +25,53 @@ func (d *decoder) setMap(rv reflect.Value, uvm urlValueMap) error {
 		}
 		rv = rv.Elem()
 	}
+	if rv.Type().Key().Kind() != reflect.String {
+		return &unsupportedTypeError{rv.Type()}
+	}
 	if rv.IsNil() {
 		rv.Set(reflect.MakeMap(rv.Type()))
 	}
 
-	for _, uv := range uvm {
-		if uv.isString && len(uv.values) == 1 {
-			rv.SetMapIndex(reflect.ValueOf(uv.key), reflect.ValueOf(uv.values[0]))
-			continue
-		}
+	if rv.Type().Elem().Kind() != reflect.Interface {
+		return d.setTypedMap(rv, uvm)
+	}
 
-		if uv.child == nil || len(uv.child) == 0 {
-			rv.SetMapIndex(reflect.ValueOf(uv.key), reflect.ValueOf(uv.values))
-			continue
-		}
+	for _, uv := range uvm {
+		rv.SetMapIndex(reflect.ValueOf(uv.key).Convert(rv.Type().Key()), reflect.ValueOf(d.makeMapValue(uv)))
+	}
+	return nil
+}
 
-		// nested array or map
-		val := d.makeMapValueRecursive(uv.child)
-		if q, ok := val.(Q); ok {
-			if aq, ok := d.toSlice(q); ok {
-				rv.SetMapIndex(reflect.ValueOf(uv.key), reflect.ValueOf(aq))
-				continue
-			}
+// setTypedMap decodes the values into the element type of the map
+func (d *decoder) setTypedMap(rv reflect.Value, uvm urlValueMap) error {
+	for _, uv := range uvm.sortedChild() {
+		crv, err := d.newElem(rv.Type().Elem(), uv, tagOption{})
+		if err != nil {
+			return wrapFieldError(uv.key, err)
 		}
-		rv.SetMapIndex(reflect.ValueOf(uv.key), reflect.ValueOf(val))
+		rv.SetMapIndex(reflect.ValueOf(uv.key).Convert(rv.Type().Key()), crv)
 	}
 	return nil
 }
 
+// makeMapValue returns the value of Q, which is string, []string, Q or S
+func (d *decoder) makeMapValue(uv urlValue) interface{} {
+	if uv.isString && len(uv.values) == 1 {
+		return uv.values[0]
+	}
+
+	if uv.child == nil || len(uv.child) == 0 {
+		return uv.values
+	}
+
+	// nested array or map
+	return d.makeMapValueRecursive(uv.child)
+}
+
 func (d *decoder) makeMapValueRecursive(valueMap urlValueMap) interface{} {
 	q := make(Q)
 	for _, uv := range valueMap {
-		if uv.isString && len(uv.values) == 1 {
-			q[uv.key] = uv.values[0]
-			continue
-		}
-
-		if uv.child == nil || len(uv.child) == 0 {
-			q[uv.key] = uv.values
-			continue
-		}
-
-		// nested array or map
-		q[uv.key] = d.makeMapValueRecursive(uv.child)
+		q[uv.key] = d.makeMapValue(uv)
 	}
 
 	if aq, ok := d.toSlice(q); ok {
@@ -91,7 +95,7 @@ func (d *decoder) toSlice(q Q) (S, bool) {
 	}
 
 	sort.Slice(tmp, func(i, j int) bool {
-		return tmp[i].key < tmp[j].key
+		return lessKey(tmp[i].key, tmp[j].key)
 	})
 
 	aq := make(S, 0, len(q))
@@ -101,3 +105,91 @@ func (d *decoder) toSlice(q Q) (S, bool) {
 
 	return aq, true
 }
+
+func (d *decoder) setInterface(rv reflect.Value, uv urlValue, opt tagOption) error {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+
+	// decode into the concrete value if the interface holds a typed pointer
+	if !rv.IsNil() && rv.Elem().Kind() == reflect.Ptr && !rv.Elem().IsNil() {
+		crv := rv.Elem().Elem()
+		return d.setTypeVlaue(crv.Type(), crv, uv, opt)
+	}
+
+	if rv.Type().NumMethod() != 0 {
+		return &unsupportedTypeError{rv.Type()}
+	}
+
+	rv.Set(reflect.ValueOf(d.makeMapValue(uv)))
+	return nil
+}
+
+func (d *decoder) decodeOrderedMap(rv reflect.Value) error {
+	valueMap, err := d.createIntermediateStruct()
+	if err != nil {
+		return err
+	}
+	d.setOrderedMapValues(rv.Addr().Interface().(*OrderedQ), valueMap)
+	return nil
+}
+
+func (d *decoder) setOrderedMap(rv reflect.Value, uv urlValue) error {
+	if !uv.hasChild() {
+		return &noAssignableValueError{rv.Type(), uv.String()}
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+	d.setOrderedMapValues(rv.Addr().Interface().(*OrderedQ), uv.child)
+	return nil
+}
+
+func (d *decoder) setOrderedMapValues(q *OrderedQ, uvm urlValueMap) {
+	for _, uv := range uvm.orderedChild() {
+		if uv.isString && len(uv.values) == 1 {
+			q.Set(uv.key, uv.values[0])
+			continue
+		}
+
+		if uv.child == nil || len(uv.child) == 0 {
+			q.Set(uv.key, uv.values)
+			continue
+		}
+
+		// nested array or map
+		cq := &OrderedQ{}
+		d.setOrderedMapValues(cq, uv.child)
+		if aq, ok := d.orderedToSlice(cq); ok {
+			q.Set(uv.key, aq)
+			continue
+		}
+		q.Set(uv.key, cq)
+	}
+}
+
+func (d *decoder) orderedToSlice(q *OrderedQ) (S, bool) {
+	keys := q.Keys()
+	for _, key := range keys {
+		if _, err := strconv.Atoi(key); err != nil {
+			return nil, false
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+
+	aq := make(S, 0, len(keys))
+	for _, key := range keys {
+		aq = append(aq, q.values[key])
+	}
+	return aq, true
+}
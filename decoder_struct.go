@@ -13,12 +13,28 @@ func (d *decoder) decodeStruct(rv reflect.Value) error {
 	return d.setStruct(rv, valueMap)
 }
 
-func (d *decoder) setTypeVlaue(rt reflect.Type, rv reflect.Value, uv urlValue) error {
+func (d *decoder) setTypeVlaue(rt reflect.Type, rv reflect.Value, uv urlValue, opt tagOption) error {
+	uv, err := d.config.resolveDuplicates(rt, uv, opt)
+	if err != nil {
+		return err
+	}
+
+	if ok, err := d.setRegistered(rt, rv, uv); ok {
+		return err
+	}
+
+	if isBytesType(rt) {
+		return d.setBytes(rt, rv, uv, opt)
+	}
+
 	switch rt.Kind() {
 	case reflect.Struct:
+		if rt == orderedQType {
+			return d.setOrderedMap(rv, uv)
+		}
 		return d.setStruct(rv, uv.child)
 	case reflect.Bool:
-		return d.setBool(rv, uv)
+		return d.setBool(rv, uv, opt)
 	case reflect.Int:
 		return d.setInt(rv, uv)
 	case reflect.Int8:
@@ -39,12 +55,18 @@ func (d *decoder) setTypeVlaue(rt reflect.Type, rv reflect.Value, uv urlValue) e
 		return d.setUint32(rv, uv)
 	case reflect.Uint64:
 		return d.setUint64(rv, uv)
+	case reflect.Float32:
+		return d.setFloat32(rv, uv)
+	case reflect.Float64:
+		return d.setFloat64(rv, uv)
 	case reflect.String:
 		return d.setString(rv, uv)
 	case reflect.Array:
-		return d.setArray(rv, uv)
+		return d.setArray(rv, uv, opt)
 	case reflect.Slice:
-		return d.setSlice(rv, uv)
+		return d.setSlice(rv, uv, opt)
+	case reflect.Interface:
+		return d.setInterface(rv, uv, opt)
 	case reflect.Map:
 		if !uv.hasChild() {
 			return &noAssignableValueError{rt, uv.String()}
@@ -67,49 +89,168 @@ func (d *decoder) setStruct(rv reflect.Value, uvm urlValueMap) error {
 		rv = rv.Elem()
 	}
 
+	var errs FieldErrors
+	// claimed is the field of each key to report the key matching several fields
+	claimed := make(map[string]string)
 	for i := 0; i < rv.NumField(); i++ {
 		f := rv.Type().Field(i)
-		if !f.IsExported() {
+		tag, opt := d.config.fieldTag(f)
+		if opt.invalid != "" {
+			return &invalidTagOptionError{rv.Type(), f.Name, opt.invalid}
+		}
+		if opt.inline {
+			if err := d.setInline(rv.Type(), rv.Field(i), uvm); err != nil {
+				if !d.collectErrors {
+					return err
+				}
+				errs = appendFieldErrors(errs, err)
+			}
 			continue
 		}
-
-		tag, _ := parseTag(f.Tag)
 		if tag == "" {
 			continue
 		}
 
-		val, ok := uvm[tag]
+		key, val, ok, err := d.lookupField(uvm, tag, opt)
+		if err == nil && ok && d.config.normalizesKeys() {
+			if other, dup := claimed[key]; dup {
+				err = &ambiguousKeyError{key, []string{other, tag}}
+			}
+			claimed[key] = tag
+		}
+		if err != nil {
+			err = wrapFieldError(tag, err)
+			if !d.collectErrors {
+				return err
+			}
+			errs = appendFieldErrors(errs, err)
+			continue
+		}
 		if !ok {
 			continue
 		}
 
-		var err error
-		frv := rv.FieldByName(f.Name)
-		if frv.Kind() == reflect.Ptr {
-			err = d.setTypeVlaue(frv.Type().Elem(), frv, val)
-		} else {
-			err = d.setTypeVlaue(frv.Type(), frv, val)
+		if err := d.setField(rv.Field(i), opt, val); err != nil {
+			err = wrapFieldError(key, err)
+			if !d.collectErrors {
+				return err
+			}
+			errs = appendFieldErrors(errs, err)
 		}
+	}
 
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// lookupField returns the value of the field and the key found in the values.
+// The key of the tag takes priority, and then the aliases in the order of the option.
+// The alias handler is called for every alias present, even if the value is not used.
+func (d *decoder) lookupField(uvm urlValueMap, tag string, opt tagOption) (string, urlValue, bool, error) {
+	found := ""
+	for i, name := range append([]string{tag}, opt.aliases...) {
+		key, ok, err := d.config.findKey(uvm, name)
 		if err != nil {
+			return "", urlValue{}, false, err
+		}
+		if !ok {
+			continue
+		}
+
+		if i > 0 && d.config.aliasHandler != nil {
+			d.config.aliasHandler(tag, name)
+		}
+		if found == "" {
+			found = key
+		}
+		if d.config.aliasHandler == nil {
+			break
+		}
+	}
+
+	if found == "" {
+		return "", urlValue{}, false, nil
+	}
+	return found, uvm[found], true, nil
+}
+
+// setInline decodes the fields of the struct from the values of the parent.
+// The pointer is allocated only if the values have any key of the struct.
+// The struct already being inlined is skipped (e.g. type Node struct { *Node }).
+func (d *decoder) setInline(parent reflect.Type, rv reflect.Value, uvm urlValueMap) error {
+	rt := rv.Type()
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return &unsupportedTypeError{rv.Type()}
+	}
+
+	if d.inlining == nil {
+		d.inlining = make(map[reflect.Type]bool)
+	}
+	defer func(v bool) { d.inlining[parent] = v }(d.inlining[parent])
+	d.inlining[parent] = true
+	if d.inlining[rt] {
+		return nil
+	}
+
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		found := false
+		for _, key := range d.config.structKeys(rt) {
+			if _, ok, _ := d.config.findKey(uvm, key); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return d.setStruct(rv, uvm)
+}
+
+func (d *decoder) setField(rv reflect.Value, opt tagOption, uv urlValue) error {
+	if opt.sealed {
+		// resolve the duplicate keys before the value is unsealed
+		var err error
+		if uv, err = d.config.resolveDuplicates(rv.Type(), uv, opt); err != nil {
+			return err
+		}
+		if uv, err = d.unsealValue(rv.Type(), uv); err != nil {
 			return err
 		}
 	}
-	return nil
+
+	if rv.Kind() == reflect.Ptr {
+		return d.setTypeVlaue(rv.Type().Elem(), rv, uv, opt)
+	}
+	return d.setTypeVlaue(rv.Type(), rv, uv, opt)
+}
+
+// unsealValue decrypts the value, the full key is authenticated as with encodeSealed
+func (d *decoder) unsealValue(rt reflect.Type, uv urlValue) (urlValue, error) {
+	if !uv.hasSingleValue() {
+		return uv, &noAssignableValueError{rt, uv.String()}
+	}
+
+	v, err := d.config.unseal(uv.path, uv.values[0])
+	if err != nil {
+		return uv, err
+	}
+	uv.values = []string{v}
+	return uv, nil
 }
 
-func (d *decoder) setBool(rv reflect.Value, uv urlValue) error {
+func (d *decoder) setBool(rv reflect.Value, uv urlValue, opt tagOption) error {
 	if !uv.hasSingleValue() {
 		return &noAssignableValueError{rv.Type(), uv.String()}
 	}
 
-	val := false
-	switch v := uv.values[0]; v {
-	case "0", "false":
-		// skip
-	case "1", "true":
-		val = true
-	default:
+	val, ok := d.config.parseBool(uv.values[0], opt)
+	if !ok {
 		return &noAssignableValueError{rv.Type(), uv.String()}
 	}
 
@@ -310,6 +451,43 @@ func (d *decoder) setUint64(rv reflect.Value, uv urlValue) error {
 	return nil
 }
 
+func (d *decoder) setFloat32(rv reflect.Value, uv urlValue) error {
+	if !uv.hasSingleValue() {
+		return &noAssignableValueError{rv.Type(), uv.String()}
+	}
+
+	f, err := strconv.ParseFloat(uv.values[0], 32)
+	if err != nil {
+		return &noAssignableValueError{rv.Type(), uv.String()}
+	}
+
+	val := float32(f)
+	if d.isPtr(rv) {
+		rv.Set(reflect.ValueOf(&val))
+	} else {
+		rv.Set(reflect.ValueOf(val))
+	}
+	return nil
+}
+
+func (d *decoder) setFloat64(rv reflect.Value, uv urlValue) error {
+	if !uv.hasSingleValue() {
+		return &noAssignableValueError{rv.Type(), uv.String()}
+	}
+
+	f, err := strconv.ParseFloat(uv.values[0], 64)
+	if err != nil {
+		return &noAssignableValueError{rv.Type(), uv.String()}
+	}
+
+	if d.isPtr(rv) {
+		rv.Set(reflect.ValueOf(&f))
+	} else {
+		rv.Set(reflect.ValueOf(f))
+	}
+	return nil
+}
+
 func (d *decoder) setString(rv reflect.Value, uv urlValue) error {
 	if !uv.hasSingleValue() {
 		return &noAssignableValueError{rv.Type(), uv.String()}
@@ -324,52 +502,68 @@ func (d *decoder) setString(rv reflect.Value, uv urlValue) error {
 	return nil
 }
 
-func (d *decoder) setArray(rv reflect.Value, uv urlValue) error {
-	val := rv
-	if d.isPtr(rv) {
-		if !rv.Elem().IsValid() {
-			rv.Set(reflect.New(rv.Type().Elem()))
-		}
-		val = rv.Elem()
+func (d *decoder) setBytes(rt reflect.Type, rv reflect.Value, uv urlValue, opt tagOption) error {
+	if !uv.hasSingleValue() {
+		return &noAssignableValueError{rv.Type(), uv.String()}
 	}
 
-	if uv.hasChild() {
-		crt := val.Type().Elem()
-		if crt.Kind() == reflect.Ptr {
-			crt = crt.Elem()
-		}
+	b, err := opt.bytes.decode(uv.values[0])
+	if err != nil {
+		return &noAssignableValueError{rv.Type(), uv.String()}
+	}
 
-		if val.Len() < len(uv.child) {
-			return &arrayIndexOutOfRangeDecodeError{val.Type(), val.Len()}
+	val := reflect.New(rt).Elem()
+	if rt.Kind() == reflect.Array {
+		if len(b) != rt.Len() {
+			return &noAssignableValueError{rv.Type(), uv.String()}
 		}
-
-		for i, cuv := range uv.child.sortedChild() {
-			crv := reflect.New(crt).Elem()
-			err := d.setTypeVlaue(crt, crv, cuv)
-			if err != nil {
-				return err
-			}
-			val.Index(i).Set(crv)
+		for i, c := range b {
+			val.Index(i).SetUint(uint64(c))
 		}
+	} else {
+		val.SetBytes(b)
+	}
 
-		return nil
+	if d.isPtr(rv) {
+		ptr := reflect.New(rt)
+		ptr.Elem().Set(val)
+		rv.Set(ptr)
+	} else {
+		rv.Set(val)
 	}
+	return nil
+}
 
-	if val.Len() < len(uv.values) {
-		return &arrayIndexOutOfRangeDecodeError{val.Type(), val.Len()}
+func (d *decoder) setArray(rv reflect.Value, uv urlValue, opt tagOption) error {
+	val := rv
+	if d.isPtr(rv) {
+		if !rv.Elem().IsValid() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		val = rv.Elem()
 	}
 
-	if val.Index(0).Type().Kind() != reflect.String {
-		return &unsupportedTypeError{val.Type()}
+	// a single empty value is how a nil value is encoded
+	if !uv.hasChild() && isEmptyParam(uv) {
+		return nil
 	}
 
-	for i, v := range uv.values {
-		val.Index(i).Set(reflect.ValueOf(v))
+	for _, e := range uv.elements() {
+		if e.index >= val.Len() {
+			return &arrayIndexOutOfRangeDecodeError{val.Type(), e.index}
+		}
+
+		crv, err := d.newElem(val.Type().Elem(), e.value, opt)
+		if err != nil {
+			return wrapFieldError(e.value.key, err)
+		}
+		val.Index(e.index).Set(crv)
 	}
 	return nil
 }
 
-func (d *decoder) setSlice(rv reflect.Value, uv urlValue) error {
+// setSlice appends the elements in index order, the gaps between the indexes are not filled
+func (d *decoder) setSlice(rv reflect.Value, uv urlValue, opt tagOption) error {
 	val := rv
 	if d.isPtr(rv) {
 		if !rv.Elem().IsValid() {
@@ -379,17 +573,10 @@ func (d *decoder) setSlice(rv reflect.Value, uv urlValue) error {
 	}
 
 	if uv.hasChild() {
-		crt := val.Type().Elem()
-		cPtr := crt.Kind() == reflect.Ptr
-		if cPtr {
-			crt = crt.Elem()
-		}
-
 		for _, cuv := range uv.child.sortedChild() {
-			crv := reflect.New(crt).Elem()
-			err := d.setTypeVlaue(crt, crv, cuv)
+			crv, err := d.newElem(val.Type().Elem(), cuv, opt)
 			if err != nil {
-				return err
+				return wrapFieldError(cuv.key, err)
 			}
 			val.Set(reflect.Append(val, crv))
 		}
@@ -397,10 +584,37 @@ func (d *decoder) setSlice(rv reflect.Value, uv urlValue) error {
 		return nil
 	}
 
-	if !val.Type().AssignableTo(reflect.TypeOf(uv.values)) {
-		return &unsupportedTypeError{val.Type()}
+	if val.Type().AssignableTo(reflect.TypeOf(uv.values)) {
+		val.Set(reflect.AppendSlice(val, reflect.ValueOf(uv.values)))
+		return nil
+	}
+
+	// a single empty value is how a nil value is encoded
+	if isEmptyParam(uv) {
+		return nil
 	}
 
-	val.Set(reflect.AppendSlice(val, reflect.ValueOf(uv.values)))
+	for i, v := range uv.values {
+		crv, err := d.newElem(val.Type().Elem(), urlValue{values: []string{v}, isString: true}, opt)
+		if err != nil {
+			return wrapFieldError(strconv.Itoa(i), err)
+		}
+		val.Set(reflect.Append(val, crv))
+	}
 	return nil
 }
+
+func isEmptyParam(uv urlValue) bool {
+	return len(uv.values) == 1 && uv.values[0] == ""
+}
+
+// newElem returns a new value of the element type rt decoded from uv.
+// The pointer is allocated by the setters if rt is a pointer.
+func (d *decoder) newElem(rt reflect.Type, uv urlValue, opt tagOption) (reflect.Value, error) {
+	crv := reflect.New(rt).Elem()
+	crt := rt
+	if crt.Kind() == reflect.Ptr {
+		crt = crt.Elem()
+	}
+	return crv, d.setTypeVlaue(crt, crv, uv, opt)
+}
@@ -462,7 +462,8 @@ func TestDecode(t *testing.T) {
 				{name: "no index", q: "field[]=1&field[]=a&field[]=true", v: &s{}, expected: s{Field: [3]string{"1", "a", "true"}}},
 				{name: "has index", q: "field[0]=1&field[1]=a&field[2]=true", v: &s{}, expected: s{Field: [3]string{"1", "a", "true"}}},
 				{name: "out of range", q: "field[0]=1&field[1]=a&field[2]=true&field[3]=b", v: &s{}, err: fmt.Errorf("index out of range [3] with [3]string")},
-				{name: "int", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf("[3]int is not supported")},
+				{name: "int", q: "field_i[0]=1&field_i[1]=2&field_i[2]=3", v: &s{}, expected: s{FieldI: [3]int{1, 2, 3}}},
+				{name: "int not assign value", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf(`"a" can not be assign to int`)},
 				{name: "no field", q: "no=1", v: &s{}, expected: s{Field: [3]string{"", "", ""}}},
 			})
 		})
@@ -475,7 +476,8 @@ func TestDecode(t *testing.T) {
 				{name: "no index", q: "field[]=1&field[]=a&field[]=true", v: &s{}, expected: s{Field: &[3]string{"1", "a", "true"}}},
 				{name: "has index", q: "field[0]=1&field[1]=a&field[2]=true", v: &s{}, expected: s{Field: &[3]string{"1", "a", "true"}}},
 				{name: "out of range", q: "field[0]=1&field[1]=a&field[2]=true&field[3]=b", v: &s{}, err: fmt.Errorf("index out of range [3] with [3]string")},
-				{name: "int", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf("[3]int is not supported")},
+				{name: "int", q: "field_i[0]=1&field_i[1]=2&field_i[2]=3", v: &s{}, expected: s{FieldI: &[3]int{1, 2, 3}}},
+				{name: "int not assign value", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf(`"a" can not be assign to int`)},
 				{name: "no field", q: "no=1", v: &s{}, expected: s{Field: nil}},
 			})
 		})
@@ -491,7 +493,8 @@ func TestDecode(t *testing.T) {
 				{name: "no index and out of range", q: "field[][]=1&field[][]=a&field[][]=true", v: &s{}, err: fmt.Errorf("index out of range [2] with [2]string")},
 				{name: "has index and out of range", q: "field[0][0]=1&field[1][0]=a&field[2][0]=true&field[3][0]=b", v: &s{}, err: fmt.Errorf("index out of range [3] with [3][2]string")},
 				{name: "child out of range", q: "field[0][0]=1&field[0][1]=a&field[0][2]=true", v: &s{}, err: fmt.Errorf("index out of range [2] with [2]string")},
-				{name: "int", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf("[3][2]int is not supported")},
+				{name: "int", q: "field_i[0][0]=1&field_i[0][1]=2&field_i[1][0]=3", v: &s{}, expected: s{FieldI: [3][2]int{{1, 2}, {3, 0}, {0, 0}}}},
+				{name: "int not assign value", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf(`"a" can not be assign to int`)},
 				{name: "no field", q: "no=1", v: &s{}, expected: s{Field: [3][2]string{{"", ""}, {"", ""}, {"", ""}}}},
 			})
 		})
@@ -506,7 +509,8 @@ func TestDecode(t *testing.T) {
 				{name: "no index and out of range", q: "field[][]=1&field[][]=a&field[][]=true", v: &s{}, err: fmt.Errorf("index out of range [2] with [2]string")},
 				{name: "has index and out of range", q: "field[0][0]=1&field[1][0]=a&field[2][0]=true&field[3][0]=b", v: &s{}, err: fmt.Errorf("index out of range [3] with [3][2]string")},
 				{name: "child out of range", q: "field[0][0]=1&field[0][1]=a&field[0][2]=true", v: &s{}, err: fmt.Errorf("index out of range [2] with [2]string")},
-				{name: "int", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf("[3][2]int is not supported")},
+				{name: "int", q: "field_i[0][0]=1&field_i[0][1]=2&field_i[1][0]=3", v: &s{}, expected: s{FieldI: &[3][2]int{{1, 2}, {3, 0}, {0, 0}}}},
+				{name: "int not assign value", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf(`"a" can not be assign to int`)},
 				{name: "no field", q: "no=1", v: &s{}, expected: s{Field: nil}},
 			})
 		})
@@ -519,7 +523,10 @@ func TestDecode(t *testing.T) {
 			runDecodeTest(t, []decodeCase{
 				{name: "no index", q: "field[]=1&field[]=a&field[]=true", v: &s{}, expected: s{Field: []string{"1", "a", "true"}}},
 				{name: "has index", q: "field[0]=1&field[1]=a&field[2]=true", v: &s{}, expected: s{Field: []string{"1", "a", "true"}}},
-				{name: "int", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf("[]int is not supported")},
+				{name: "sparse index", q: "field[2]=b&field[1]=a", v: &s{}, expected: s{Field: []string{"a", "b"}}},
+				{name: "huge index", q: "field_i[99999999999999]=1&field_i[100000000]=2", v: &s{}, expected: s{FieldI: []int{2, 1}}},
+				{name: "int", q: "field_i[0]=1&field_i[1]=2&field_i[2]=3", v: &s{}, expected: s{FieldI: []int{1, 2, 3}}},
+				{name: "int not assign value", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf(`"a" can not be assign to int`)},
 				{name: "no field", q: "no=1", v: &s{}, expected: s{Field: nil}},
 			})
 		})
@@ -531,7 +538,8 @@ func TestDecode(t *testing.T) {
 			runDecodeTest(t, []decodeCase{
 				{name: "no index", q: "field[]=1&field[]=a&field[]=true", v: &s{}, expected: s{Field: &[]string{"1", "a", "true"}}},
 				{name: "has index", q: "field[0]=1&field[1]=a&field[2]=true", v: &s{}, expected: s{Field: &[]string{"1", "a", "true"}}},
-				{name: "int", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf("[]int is not supported")},
+				{name: "int", q: "field_i[]=1&field_i[]=2", v: &s{}, expected: s{FieldI: []int{1, 2}}},
+				{name: "int not assign value", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf(`"a" can not be assign to int`)},
 				{name: "no field", q: "no=1", v: &s{}, expected: s{Field: nil}},
 			})
 		})
@@ -544,7 +552,8 @@ func TestDecode(t *testing.T) {
 			runDecodeTest(t, []decodeCase{
 				{name: "no index", q: "field[][]=1&field[][]=a&field[][]=true", v: &s{}, expected: s{Field: [][]string{{"1", "a", "true"}}}},
 				{name: "has index", q: "field[0][0]=1&field[0][1]=a&field[1][0]=true", v: &s{}, expected: s{Field: [][]string{{"1", "a"}, {"true"}}}},
-				{name: "int", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf("[][]int is not supported")},
+				{name: "int", q: "field_i[0][0]=1&field_i[0][1]=2&field_i[1][0]=3", v: &s{}, expected: s{FieldI: [][]int{{1, 2}, {3}}}},
+				{name: "int not assign value", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf(`"a" can not be assign to int`)},
 				{name: "no field", q: "no=1", v: &s{}, expected: s{Field: nil}},
 			})
 		})
@@ -556,7 +565,8 @@ func TestDecode(t *testing.T) {
 			runDecodeTest(t, []decodeCase{
 				{name: "no index", q: "field[][]=1&field[][]=a&field[][]=true", v: &s{}, expected: s{Field: &[][]string{{"1", "a", "true"}}}},
 				{name: "has index", q: "field[0][0]=1&field[0][1]=a&field[1][0]=true", v: &s{}, expected: s{Field: &[][]string{{"1", "a"}, {"true"}}}},
-				{name: "int", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf("[][]int is not supported")},
+				{name: "int", q: "field_i[0][0]=1&field_i[0][1]=2&field_i[1][0]=3", v: &s{}, expected: s{FieldI: &[][]int{{1, 2}, {3}}}},
+				{name: "int not assign value", q: "field_i[0]=1&field_i[1]=a&field_i[2]=true", v: &s{}, err: fmt.Errorf(`"a" can not be assign to int`)},
 				{name: "no field", q: "no=1", v: &s{}, expected: s{Field: nil}},
 			})
 		})
@@ -619,6 +629,51 @@ func TestDecode(t *testing.T) {
 				{name: "no field", q: "no=1", v: &s{}, expected: s{Field: nil}},
 			})
 		})
+
+		t.Run("interface", func(t *testing.T) {
+			type s struct {
+				Field interface{} `qstring:"field"`
+			}
+			runDecodeTest(t, []decodeCase{
+				{name: "string", q: "field=a", v: &s{}, expected: s{Field: "a"}},
+				{name: "multiple values", q: "field=a&field=b", v: &s{}, expected: s{Field: []string{"a", "b"}}},
+				{name: "array", q: "field[]=a&field[]=b", v: &s{}, expected: s{Field: []string{"a", "b"}}},
+				{name: "map", q: "field[a]=1&field[b][c]=2", v: &s{}, expected: s{Field: qstring.Q{"a": "1", "b": qstring.Q{"c": "2"}}}},
+				{name: "nested array", q: "field[0][a]=1&field[1][a]=2", v: &s{}, expected: s{Field: qstring.S{qstring.Q{"a": "1"}, qstring.Q{"a": "2"}}}},
+				{name: "replace non-pointer value", q: "field=a", v: &s{Field: 1}, expected: s{Field: "a"}},
+				{name: "typed pointer", q: "field=1", v: &s{Field: intP(0)}, expected: s{Field: intP(1)}},
+				{name: "typed pointer error", q: "field=a", v: &s{Field: intP(0)}, err: fmt.Errorf(`"a" can not be assign to int`)},
+				{name: "no field", q: "no=1", v: &s{}, expected: s{Field: nil}},
+			})
+		})
+		t.Run("interface pointer", func(t *testing.T) {
+			type s struct {
+				Field *interface{} `qstring:"field"`
+			}
+			runDecodeTest(t, []decodeCase{
+				{name: "string", q: "field=a", v: &s{}, expected: s{Field: func() *interface{} { var v interface{} = "a"; return &v }()}},
+				{name: "no field", q: "no=1", v: &s{}, expected: s{Field: nil}},
+			})
+		})
+		t.Run("non-empty interface", func(t *testing.T) {
+			type s struct {
+				Field fmt.Stringer `qstring:"field"`
+			}
+			runDecodeTest(t, []decodeCase{
+				{name: "nil", q: "field=a", v: &s{}, err: fmt.Errorf("fmt.Stringer is not supported")},
+			})
+		})
+		t.Run("struct with typed pointer interface", func(t *testing.T) {
+			type c struct {
+				Name string `qstring:"name"`
+			}
+			type s struct {
+				Field interface{} `qstring:"field"`
+			}
+			runDecodeTest(t, []decodeCase{
+				{name: "struct", q: "field[name]=a", v: &s{Field: &c{}}, expected: s{Field: &c{Name: "a"}}},
+			})
+		})
 	})
 }
 
@@ -669,3 +724,147 @@ func complex128P(v complex128) *complex128 { return &v }
 func complex64P(v complex64) *complex64    { return &v }
 func stringP(v string) *string             { return &v }
 func unsafeP(v string) unsafe.Pointer      { return unsafe.Pointer(stringP(v)) }
+
+func TestDecode_bytes(t *testing.T) {
+	type s struct {
+		Data   []byte   `qstring:"data"`
+		DataP  *[]byte  `qstring:"data_p"`
+		Hex    []byte   `qstring:"hex,hex"`
+		Std    []byte   `qstring:"std,base64std"`
+		Hash   [4]byte  `qstring:"hash,hex"`
+		HashP  *[4]byte `qstring:"hash_p,hex"`
+		Chunks [][]byte `qstring:"chunks"`
+	}
+
+	runDecodeTest(t, []decodeCase{
+		{name: "base64url", q: "data=aGk_Pz4-", v: &s{}, expected: s{Data: []byte("hi??>>")}},
+		{name: "base64url with padding", q: "data=aGk%3D", v: &s{}, expected: s{Data: []byte("hi")}},
+		{name: "pointer", q: "data_p=aGk", v: &s{}, expected: s{DataP: func() *[]byte { b := []byte("hi"); return &b }()}},
+		{name: "hex", q: "hex=6869", v: &s{}, expected: s{Hex: []byte("hi")}},
+		{name: "base64std", q: "std=aGk/Pz4%2B", v: &s{}, expected: s{Std: []byte("hi??>>")}},
+		{name: "array", q: "hash=01020304", v: &s{}, expected: s{Hash: [4]byte{1, 2, 3, 4}}},
+		{name: "array pointer", q: "hash_p=01020304", v: &s{}, expected: s{HashP: &[4]byte{1, 2, 3, 4}}},
+		{name: "slice of bytes", q: "chunks[]=aGk&chunks[]=eW8", v: &s{}, expected: s{Chunks: [][]byte{[]byte("hi"), []byte("yo")}}},
+		{name: "array length mismatch", q: "hash=0102", v: &s{}, err: fmt.Errorf(`"0102" can not be assign to [4]uint8`)},
+		{name: "invalid hex", q: "hex=zz", v: &s{}, err: fmt.Errorf(`"zz" can not be assign to []uint8`)},
+		{name: "multiple values", q: "data=a&data=b", v: &s{}, err: fmt.Errorf(`key "data" is given 2 times`)},
+	})
+}
+
+func TestDecode_pointerElements(t *testing.T) {
+	type item struct {
+		Name string `qstring:"name"`
+		Qty  *int   `qstring:"qty"`
+	}
+	type s struct {
+		Items   []*item            `qstring:"items"`
+		ItemsA  [2]*item           `qstring:"items_a"`
+		ItemsP  *[]item            `qstring:"items_p"`
+		ItemsPP *[]*item           `qstring:"items_pp"`
+		Nested  [][]*item          `qstring:"nested"`
+		NestedA [2][]*item         `qstring:"nested_a"`
+		Map     map[string][]*item `qstring:"map"`
+		MapP    map[string]*item   `qstring:"map_p"`
+		Strs    []*string          `qstring:"strs"`
+		StrsA   [2]*string         `qstring:"strs_a"`
+	}
+
+	runDecodeTest(t, []decodeCase{
+		{name: "slice", q: "items[0][name]=a&items[0][qty]=1&items[1][name]=b", v: &s{},
+			expected: s{Items: []*item{{Name: "a", Qty: intP(1)}, {Name: "b"}}}},
+		{name: "array", q: "items_a[0][name]=a&items_a[1][qty]=2", v: &s{},
+			expected: s{ItemsA: [2]*item{{Name: "a"}, {Qty: intP(2)}}}},
+		{name: "array out of range", q: "items_a[0][name]=a&items_a[1][name]=b&items_a[2][name]=c", v: &s{},
+			err: fmt.Errorf("index out of range [2] with [2]*qstring_test.item")},
+		{name: "sparse index", q: "items[2][name]=c&items[0][name]=a", v: &s{},
+			expected: s{Items: []*item{{Name: "a"}, {Name: "c"}}}},
+		{name: "sparse index of string pointers", q: "strs_a[1]=b", v: &s{},
+			expected: s{StrsA: [2]*string{nil, stringP("b")}}},
+		{name: "pointer to slice", q: "items_p[0][name]=a&items_p[1][name]=b", v: &s{},
+			expected: s{ItemsP: &[]item{{Name: "a"}, {Name: "b"}}}},
+		{name: "pointer to slice of pointers", q: "items_pp[0][name]=a", v: &s{},
+			expected: s{ItemsPP: &[]*item{{Name: "a"}}}},
+		{name: "nested slice", q: "nested[0][0][name]=a&nested[0][1][name]=b&nested[1][0][name]=c", v: &s{},
+			expected: s{Nested: [][]*item{{{Name: "a"}, {Name: "b"}}, {{Name: "c"}}}}},
+		{name: "array of slices", q: "nested_a[0][0][name]=a&nested_a[1][0][name]=b&nested_a[1][1][name]=c", v: &s{},
+			expected: s{NestedA: [2][]*item{{{Name: "a"}}, {{Name: "b"}, {Name: "c"}}}}},
+		{name: "map of slices", q: "map[x][0][name]=a&map[x][1][name]=b&map[y][0][qty]=3", v: &s{},
+			expected: s{Map: map[string][]*item{"x": {{Name: "a"}, {Name: "b"}}, "y": {{Qty: intP(3)}}}}},
+		{name: "map of pointers", q: "map_p[x][name]=a", v: &s{},
+			expected: s{MapP: map[string]*item{"x": {Name: "a"}}}},
+		{name: "slice of string pointers", q: "strs[]=a&strs[]=b", v: &s{},
+			expected: s{Strs: []*string{stringP("a"), stringP("b")}}},
+		{name: "sparse index of flat slice", q: "strs[2]=b&strs[1]=a", v: &s{},
+			expected: s{Strs: []*string{stringP("a"), stringP("b")}}},
+		{name: "array of string pointers", q: "strs_a[]=a&strs_a[]=b", v: &s{},
+			expected: s{StrsA: [2]*string{stringP("a"), stringP("b")}}},
+		{name: "not assign value", q: "items[0][qty]=a", v: &s{},
+			err: fmt.Errorf(`"a" can not be assign to *int`)},
+	})
+}
+
+func TestDecode_multiDimensional(t *testing.T) {
+	type s struct {
+		Grid   [][]int       `qstring:"grid"`
+		Matrix [3][2]float64 `qstring:"matrix"`
+		Cube   [][][]string  `qstring:"cube"`
+		Flags  *[2][]bool    `qstring:"flags"`
+		Mixed  [][2]*float64 `qstring:"mixed"`
+	}
+
+	runDecodeTest(t, []decodeCase{
+		{name: "slice of int slices", q: "grid[0][0]=1&grid[0][1]=2&grid[1][0]=3&grid[1][1]=4", v: &s{},
+			expected: s{Grid: [][]int{{1, 2}, {3, 4}}}},
+		{name: "float matrix", q: "matrix[0][0]=1.5&matrix[0][1]=-2&matrix[1][0]=0.25", v: &s{},
+			expected: s{Matrix: [3][2]float64{{1.5, -2}, {0.25, 0}, {0, 0}}}},
+		{name: "more than ten rows", q: "grid[0][]=0&grid[1][]=1&grid[2][]=2&grid[3][]=3&grid[4][]=4&grid[5][]=5&grid[6][]=6&grid[7][]=7&grid[8][]=8&grid[9][]=9&grid[10][]=10", v: &s{},
+			expected: s{Grid: [][]int{{0}, {1}, {2}, {3}, {4}, {5}, {6}, {7}, {8}, {9}, {10}}}},
+		{name: "empty value", q: "grid=&flags=", v: &s{},
+			expected: s{Flags: &[2][]bool{}}},
+		{name: "three dimensions", q: "cube[0][0][0]=a&cube[0][0][1]=b&cube[0][1][0]=c&cube[1][0][0]=d", v: &s{},
+			expected: s{Cube: [][][]string{{{"a", "b"}, {"c"}}, {{"d"}}}}},
+		{name: "pointer to array of slices", q: "flags[0][]=true&flags[0][]=false&flags[1][]=true", v: &s{},
+			expected: s{Flags: &[2][]bool{{true, false}, {true}}}},
+		{name: "slice of pointer arrays", q: "mixed[0][0]=1&mixed[0][1]=2", v: &s{},
+			expected: s{Mixed: [][2]*float64{{float64P(1), float64P(2)}}}},
+		{name: "sparse index of array", q: "matrix[2][1]=5", v: &s{},
+			expected: s{Matrix: [3][2]float64{{0, 0}, {0, 0}, {0, 5}}}},
+		{name: "sparse index of slice", q: "grid[3][0]=7&grid[1][2]=8", v: &s{},
+			expected: s{Grid: [][]int{{8}, {7}}}},
+		{name: "huge index of slice", q: "grid[99999999999999][100000000]=1", v: &s{},
+			expected: s{Grid: [][]int{{1}}}},
+		{name: "inner out of range", q: "matrix[0][0]=1&matrix[0][1]=2&matrix[0][2]=3", v: &s{},
+			err: fmt.Errorf("index out of range [2] with [2]float64")},
+		{name: "sparse index out of range", q: "matrix[0][5]=1", v: &s{},
+			err: fmt.Errorf("index out of range [5] with [2]float64")},
+		{name: "outer index out of range", q: "matrix[3][0]=1", v: &s{},
+			err: fmt.Errorf("index out of range [3] with [3][2]float64")},
+		{name: "huge index of array", q: "matrix[99999999999999][0]=1", v: &s{},
+			err: fmt.Errorf("index out of range [99999999999999] with [3][2]float64")},
+		{name: "not assign value", q: "grid[0][0]=1&grid[0][1]=x", v: &s{},
+			err: fmt.Errorf(`"x" can not be assign to int`)},
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		in := s{
+			Grid:   [][]int{{1, 2}, {3}},
+			Matrix: [3][2]float64{{1.5, 2}, {3, 4.25}, {5, 6}},
+			Cube:   [][][]string{{{"a"}, {"b", "c"}}},
+		}
+		q, err := qstring.Encode(in)
+		if err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		if !strings.Contains(q, "grid%5B1%5D%5B0%5D=3") || !strings.Contains(q, "matrix%5B1%5D%5B1%5D=4.25") {
+			t.Errorf("Encode() returns %s, want key[i][j] parameters", q)
+		}
+
+		var out s
+		if err := qstring.Decode(q, &out); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if !reflect.DeepEqual(out.Grid, in.Grid) || out.Matrix != in.Matrix || !reflect.DeepEqual(out.Cube, in.Cube) {
+			t.Errorf("Decode() returns %#v, want %#v", out, in)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package qstring
+
+import (
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DuplicatePolicy is the rule when a key of a single value field is given more than once
+// (e.g. "?id=1&id=2" for an int field).
+type DuplicatePolicy int
+
+const (
+	duplicateDefault DuplicatePolicy = iota
+	// DuplicateError returns a DuplicateKeyError, this is the default.
+	DuplicateError
+	// DuplicateFirst uses the first value.
+	DuplicateFirst
+	// DuplicateLast uses the last value.
+	DuplicateLast
+	// DuplicateJoin joins the values with the separator, the default separator is ",".
+	DuplicateJoin
+)
+
+const (
+	// dupOpt is the prefix of the option "dup=first|last|join|error"
+	dupOpt = "dup="
+	// sepOpt is the prefix of the option "sep=<separator>" for DuplicateJoin
+	sepOpt = "sep="
+
+	defaultJoinSeparator = ","
+)
+
+var duplicatePolicies = map[string]DuplicatePolicy{
+	"error": DuplicateError,
+	"first": DuplicateFirst,
+	"last":  DuplicateLast,
+	"join":  DuplicateJoin,
+}
+
+// WithDuplicatePolicy sets the rule when a key of a single value field is given more than once.
+// The separator is used by DuplicateJoin, it is "," if empty.
+//
+// The policy of the field can be set by the tag option "dup=first|last|join|error"
+// (e.g. `qstring:"id,dup=last"`), and the separator by "sep=<separator>" (e.g. `qstring:"tags,dup=join,sep=;"`).
+// The separator of the tag is percent-encoded, write "sep=%2C" for "," and "sep=%20" for " ".
+// Decoder returns an error for an unknown policy of the tag.
+func WithDuplicatePolicy(policy DuplicatePolicy, sep string) Option {
+	return func(c *config) {
+		c.duplicatePolicy = policy
+		c.joinSeparator = sep
+	}
+}
+
+// DuplicateKeyError is returned when a key of a single value field is given more than once
+// with DuplicateError.
+type DuplicateKeyError struct {
+	// Key is the key of the field.
+	Key string
+	// Values is the given values.
+	Values []string
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return `key "` + e.Key + `" is given ` + strconv.Itoa(len(e.Values)) + " times"
+}
+
+// resolveDuplicates reduces the values of the single value field to one value by the policy
+func (c *config) resolveDuplicates(rt reflect.Type, uv urlValue, opt tagOption) (urlValue, error) {
+	if len(uv.values) <= 1 || uv.hasChild() || !c.isSingleValueType(rt) {
+		return uv, nil
+	}
+
+	policy, sep := opt.duplicate, opt.joinSeparator
+	if policy == duplicateDefault {
+		policy = c.duplicatePolicy
+	}
+	if sep == "" {
+		sep = c.joinSeparator
+	}
+	if sep == "" {
+		sep = defaultJoinSeparator
+	}
+
+	switch policy {
+	case DuplicateFirst:
+		uv.values = uv.values[:1]
+	case DuplicateLast:
+		uv.values = uv.values[len(uv.values)-1:]
+	case DuplicateJoin:
+		uv.values = []string{strings.Join(uv.values, sep)}
+	default:
+		return uv, &DuplicateKeyError{Key: uv.key, Values: uv.values}
+	}
+	uv.isString = true
+	return uv, nil
+}
+
+func (c *config) isSingleValueType(rt reflect.Type) bool {
+	if _, ok := c.typeDecoder(rt); ok {
+		return true
+	}
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if _, ok := c.typeDecoder(rt); ok || isBytesType(rt) {
+		return true
+	}
+
+	switch rt.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// PollutedParam is a key given in both the URL query and the form body.
+type PollutedParam struct {
+	Key   string
+	Query []string
+	Form  []string
+}
+
+// FindPollution returns the keys given in both the URL query and the form body,
+// known as HTTP parameter pollution, sorted by key.
+// The keys repeated in only one of them are not reported, as they are the lists like "tags[]=a&tags[]=b".
+//
+// For example, FindPollution(r.URL.Query(), r.PostForm) after r.ParseForm.
+func FindPollution(query, form url.Values) []PollutedParam {
+	var params []PollutedParam
+	for k, vs := range query {
+		if len(vs) > 0 && len(form[k]) > 0 {
+			params = append(params, PollutedParam{Key: k, Query: vs, Form: form[k]})
+		}
+	}
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Key < params[j].Key
+	})
+	return params
+}
@@ -0,0 +1,138 @@
+package qstring_test
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestWithDuplicatePolicy(t *testing.T) {
+	type s struct {
+		ID    int      `qstring:"id"`
+		Name  *string  `qstring:"name"`
+		Tags  string   `qstring:"tags,dup=join,sep=;"`
+		Last  string   `qstring:"last,dup=last"`
+		Items []string `qstring:"items"`
+	}
+
+	q := "id=1&id=2&name=a&name=b&tags=x&tags=y&last=p&last=q&items=m&items=n"
+
+	testCases := []struct {
+		name     string
+		dec      *qstring.Decoder
+		q        string
+		expected s
+		err      error
+	}{
+		{name: "default", dec: qstring.NewDecoder(), q: q, err: fmt.Errorf(`key "id" is given 2 times`)},
+		{name: "error", dec: qstring.NewDecoder(qstring.WithDuplicatePolicy(qstring.DuplicateError, "")), q: "name=a&name=b",
+			err: fmt.Errorf(`key "name" is given 2 times`)},
+		{name: "first", dec: qstring.NewDecoder(qstring.WithDuplicatePolicy(qstring.DuplicateFirst, "")), q: q,
+			expected: s{ID: 1, Name: stringP("a"), Tags: "x;y", Last: "q", Items: []string{"m", "n"}}},
+		{name: "last", dec: qstring.NewDecoder(qstring.WithDuplicatePolicy(qstring.DuplicateLast, "")), q: q,
+			expected: s{ID: 2, Name: stringP("b"), Tags: "x;y", Last: "q", Items: []string{"m", "n"}}},
+		{name: "join", dec: qstring.NewDecoder(qstring.WithDuplicatePolicy(qstring.DuplicateJoin, "")), q: "name=a&name=b&tags=x&tags=y",
+			expected: s{Name: stringP("a,b"), Tags: "x;y"}},
+		{name: "join with separator", dec: qstring.NewDecoder(qstring.WithDuplicatePolicy(qstring.DuplicateJoin, " ")), q: "name=a&name=b",
+			expected: s{Name: stringP("a b")}},
+		{name: "join is not a number", dec: qstring.NewDecoder(qstring.WithDuplicatePolicy(qstring.DuplicateJoin, "")), q: "id=1&id=2",
+			err: fmt.Errorf(`"1,2" can not be assign to int`)},
+		{name: "per field without global", dec: qstring.NewDecoder(), q: "tags=x&tags=y&last=p&last=q",
+			expected: s{Tags: "x;y", Last: "q"}},
+		{name: "single value", dec: qstring.NewDecoder(), q: "id=1&name=a", expected: s{ID: 1, Name: stringP("a")}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual s
+			err := tc.dec.Decode(tc.q, &actual)
+			assertResult(t, "Decode()", actual, err, tc.expected, tc.err)
+		})
+	}
+
+	t.Run("typed map and DecodeAt", func(t *testing.T) {
+		dec := qstring.NewDecoder(qstring.WithDuplicatePolicy(qstring.DuplicateLast, ""))
+
+		var m map[string]int
+		err := dec.Decode("m[a]=1&m[a]=2&m[b]=3", &struct {
+			M *map[string]int `qstring:"m"`
+		}{&m})
+		assertResult(t, "Decode()", m, err, map[string]int{"a": 2, "b": 3}, nil)
+
+		var id int
+		err = dec.DecodeAt("f[id]=1&f[id]=2", "f.id", &id)
+		assertResult(t, "DecodeAt()", id, err, 2, nil)
+
+		var ids [2]int
+		err = dec.Decode("ids[0]=1&ids[0]=2&ids[1]=3", &struct {
+			IDs *[2]int `qstring:"ids"`
+		}{&ids})
+		assertResult(t, "Decode()", ids, err, [2]int{2, 3}, nil)
+
+		err = qstring.DecodeAt("f[id]=1&f[id]=2", "f.id", &id)
+		assertResult(t, "DecodeAt()", nil, err, nil, fmt.Errorf(`key "id" is given 2 times`))
+	})
+
+	t.Run("tag options", func(t *testing.T) {
+		type escaped struct {
+			Tags string `qstring:"tags,dup=join,sep=%2C%20"`
+		}
+		type unknown struct {
+			ID int `qstring:"id,dup=lst"`
+		}
+
+		var e escaped
+		err := qstring.Decode("tags=x&tags=y", &e)
+		assertResult(t, "Decode()", e, err, escaped{Tags: "x, y"}, nil)
+
+		err = qstring.Decode("id=1", &unknown{})
+		assertResult(t, "Decode()", nil, err, nil, fmt.Errorf(`invalid tag option "dup=lst" of qstring_test.unknown.ID`))
+		_, err = qstring.Encode(unknown{})
+		assertResult(t, "Encode()", nil, err, nil, fmt.Errorf(`invalid tag option "dup=lst" of qstring_test.unknown.ID`))
+	})
+
+	t.Run("error type", func(t *testing.T) {
+		type parent struct {
+			Child s `qstring:"child"`
+		}
+		err := qstring.Decode("child[id]=1&child[id]=2", &parent{})
+
+		var de *qstring.DuplicateKeyError
+		if !errors.As(err, &de) || de.Key != "id" || !reflect.DeepEqual(de.Values, []string{"1", "2"}) {
+			t.Fatalf("Decode() error = %#v, want DuplicateKeyError", err)
+		}
+		var fe *qstring.FieldError
+		if !errors.As(err, &fe) || fe.Key != "child[id]" {
+			t.Errorf("Decode() error = %#v, want the key child[id]", err)
+		}
+	})
+}
+
+func TestFindPollution(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    url.Values
+		form     url.Values
+		expected []qstring.PollutedParam
+	}{
+		{name: "none", query: url.Values{"a": {"1"}}, form: url.Values{"b": {"2"}}},
+		{name: "nil", query: nil, form: nil},
+		{name: "query and form", query: url.Values{"id": {"1"}, "a": {"1"}}, form: url.Values{"id": {"2"}},
+			expected: []qstring.PollutedParam{{Key: "id", Query: []string{"1"}, Form: []string{"2"}}}},
+		{name: "sorted", query: url.Values{"id": {"1"}, "b": {"1", "2"}}, form: url.Values{"id": {"2"}, "b": {"3"}},
+			expected: []qstring.PollutedParam{{Key: "b", Query: []string{"1", "2"}, Form: []string{"3"}}, {Key: "id", Query: []string{"1"}, Form: []string{"2"}}}},
+		{name: "list in query only", query: url.Values{"tags[]": {"a", "b"}}, form: url.Values{"id": {"1"}}},
+		{name: "list in form only", query: nil, form: url.Values{"tags[]": {"a", "b"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := qstring.FindPollution(tc.query, tc.form)
+			assertResult(t, "FindPollution()", actual, nil, tc.expected, nil)
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package qstring
 
 import (
+	"fmt"
 	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -13,80 +15,188 @@ const (
 )
 
 type encoder struct {
-	v url.Values
+	config config
+	v      url.Values
+	// keys is the keys of v in the order they were added
+	keys []string
+	// ordered outputs the keys in the order they were added instead of sorting them
+	ordered bool
+	// sortKeys is the keys to sort the values of OrderedQ in the order they were added
+	sortKeys map[string]string
 }
 
 func (e *encoder) encode(v interface{}) (string, error) {
+	rv, err := e.indirect(v)
+	if err != nil {
+		return "", err
+	}
+
+	if rv.Kind() == reflect.String {
+		return e.encodeString(rv), nil
+	}
+
+	if err := e.encodeValues(rv); err != nil {
+		return "", err
+	}
+
+	if len(e.v) == 0 {
+		return "", nil
+	}
+	return strings.Join(e.params(), "&"), nil
+}
+
+func (e *encoder) values(v interface{}) (url.Values, error) {
+	rv, err := e.indirect(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if rv.Kind() == reflect.String {
+		e.v = make(url.Values)
+		// keep the order of the string as written
+		e.ordered = true
+		for _, s := range strings.Split(strings.TrimPrefix(rv.String(), que), "&") {
+			if s == "" {
+				continue
+			}
+			key, value := s, ""
+			if i := strings.Index(s, "="); i >= 0 {
+				key, value = s[:i], s[i+1:]
+			}
+			e.add(key, value)
+		}
+		return e.v, nil
+	}
+
+	if err := e.encodeValues(rv); err != nil {
+		return nil, err
+	}
+	return e.v, nil
+}
+
+func (e *encoder) indirect(v interface{}) (reflect.Value, error) {
 	rv := reflect.ValueOf(v)
 	if v == nil || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
-		return "", &invalidEncodeError{reflect.TypeOf(v)}
+		return rv, &invalidEncodeError{reflect.TypeOf(v)}
 	}
 
-	e.v = make(url.Values)
-
 	if rv.Kind() == reflect.Ptr {
 		rv = rv.Elem()
 	}
+	return rv, nil
+}
+
+func (e *encoder) encodeValues(rv reflect.Value) error {
+	e.v = make(url.Values)
 
 	switch rv.Kind() {
 	case reflect.Map:
-		if err := e.encodeMap("", rv); err != nil {
-			return "", err
-		}
+		return e.encodeMap("", rv, tagOption{})
 	case reflect.Struct:
-		if err := e.encodeStruct("", rv); err != nil {
-			return "", err
+		if rv.Type() == orderedQType {
+			e.ordered = true
+			return e.encodeOrderedMap("", rv, tagOption{})
 		}
-	case reflect.String:
-		return e.encodeString(rv), nil
-	default:
-		return "", &unsupportedTypeError{rv.Type()}
+		return e.encodeStruct("", rv)
+	}
+	return &unsupportedTypeError{rv.Type()}
+}
+
+func (e *encoder) add(key, value string) {
+	if _, ok := e.v[key]; !ok {
+		e.keys = append(e.keys, key)
 	}
+	e.v.Add(key, value)
+}
 
-	if len(e.v) == 0 {
-		return "", nil
+// params returns the encoded "key=value" pairs
+func (e *encoder) params() []string {
+	params := make([]string, 0, len(e.keys))
+	for _, k := range e.orderedKeys() {
+		params = append(params, e.config.encodeParams(k, e.v[k])...)
+	}
+	return params
+}
+
+// orderedKeys returns the keys in the order of the output.
+// The keys are sorted unless the order is preserved.
+func (e *encoder) orderedKeys() []string {
+	if e.ordered {
+		return e.keys
+	}
+	keys := make([]string, len(e.keys))
+	copy(keys, e.keys)
+	if e.sortKeys == nil {
+		sort.Strings(keys)
+		return keys
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return e.sortKey(keys[i]) < e.sortKey(keys[j])
+	})
+	return keys
+}
+
+// sortKey returns the key for sorting,
+// the keys of OrderedQ are replaced with their positions.
+func (e *encoder) sortKey(key string) string {
+	for i := len(key); i > 0; i = strings.LastIndex(key[:i], "[") {
+		if sk, ok := e.sortKeys[key[:i]]; ok {
+			return sk + key[i:]
+		}
 	}
-	return e.v.Encode(), nil
+	return key
 }
 
-func (e *encoder) encodeByType(key string, rv reflect.Value) error {
+func (e *encoder) encodeByType(key string, rv reflect.Value, opt tagOption) error {
+	if encode, ok := e.config.typeEncoder(rv.Type()); ok {
+		v, err := encode(rv)
+		if err != nil {
+			return err
+		}
+		e.add(key, v)
+		return nil
+	}
+
+	if isBytesType(rv.Type()) {
+		return e.encodeBytes(key, rv, opt)
+	}
+
 	switch rv.Kind() {
 	case reflect.Bool:
-		bv := "true"
-		if !rv.Bool() {
-			bv = "false"
-		}
-		e.v.Add(key, bv)
+		e.add(key, formatBool(rv.Bool(), opt))
 	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
-		e.v.Add(key, strconv.FormatInt(rv.Int(), 10))
+		e.add(key, strconv.FormatInt(rv.Int(), 10))
 	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
-		e.v.Add(key, strconv.FormatUint(rv.Uint(), 10))
+		e.add(key, strconv.FormatUint(rv.Uint(), 10))
 	case reflect.Float32:
-		e.v.Add(key, strconv.FormatFloat(rv.Float(), 'f', -1, 32))
+		e.add(key, strconv.FormatFloat(rv.Float(), 'f', -1, 32))
 	case reflect.Float64:
-		e.v.Add(key, strconv.FormatFloat(rv.Float(), 'f', -1, 64))
+		e.add(key, strconv.FormatFloat(rv.Float(), 'f', -1, 64))
 	case reflect.Map:
-		return e.encodeMap(key, rv)
+		return e.encodeMap(key, rv, opt)
 	case reflect.Array:
-		return e.encodeArray(key, rv)
+		return e.encodeArray(key, rv, opt)
 	case reflect.Slice:
-		return e.encodeSlice(key, rv)
+		return e.encodeSlice(key, rv, opt)
 	case reflect.Struct:
+		if rv.Type() == orderedQType {
+			return e.encodeOrderedMap(key, rv, opt)
+		}
 		return e.encodeStruct(key, rv)
 	case reflect.String:
-		e.v.Add(key, rv.String())
+		e.add(key, rv.String())
 	case reflect.Interface:
 		if rv.IsNil() {
-			e.v.Add(key, defaultNilValue)
+			e.add(key, defaultNilValue)
 			return nil
 		}
-		return e.encodeByType(key, reflect.ValueOf(rv.Interface()))
+		return e.encodeByType(key, reflect.ValueOf(rv.Interface()), opt)
 	case reflect.Ptr:
 		if rv.IsNil() {
-			e.v.Add(key, defaultNilValue)
+			e.add(key, defaultNilValue)
 			return nil
 		}
-		return e.encodeByType(key, reflect.Indirect(rv))
+		return e.encodeByType(key, reflect.Indirect(rv), opt)
 	default:
 		return &unsupportedTypeError{rv.Type()}
 	}
@@ -94,6 +204,15 @@ func (e *encoder) encodeByType(key string, rv reflect.Value) error {
 	return nil
 }
 
+func (e *encoder) encodeBytes(key string, rv reflect.Value, opt tagOption) error {
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		e.add(key, defaultNilValue)
+		return nil
+	}
+	e.add(key, opt.bytes.encode(bytesOf(rv)))
+	return nil
+}
+
 func (e *encoder) encodeString(rv reflect.Value) string {
 	q := ""
 	if strings.HasPrefix(rv.String(), que) {
@@ -104,15 +223,21 @@ func (e *encoder) encodeString(rv reflect.Value) string {
 	encoded := make([]string, 0, len(queries))
 
 	for _, s := range queries {
-		encoded = append(encoded, strings.ReplaceAll(url.QueryEscape(s), "%3D", "="))
+		i := strings.Index(s, "=")
+		if i == -1 {
+			encoded = append(encoded, e.config.escapeKey(s))
+			continue
+		}
+		value := strings.ReplaceAll(e.config.escapeValue(s[i+1:]), "%3D", "=")
+		encoded = append(encoded, e.config.escapeKey(s[:i])+"="+value)
 	}
 	return q + strings.Join(encoded, "&")
 }
 
-func (e *encoder) encodeMap(key string, rv reflect.Value) error {
+func (e *encoder) encodeMap(key string, rv reflect.Value, opt tagOption) error {
 	if rv.IsNil() || rv.Len() == 0 {
 		if key != "" {
-			e.v.Add(key, defaultNilValue)
+			e.add(key, defaultNilValue)
 		}
 		return nil
 	}
@@ -122,62 +247,144 @@ func (e *encoder) encodeMap(key string, rv reflect.Value) error {
 		return &unsupportedTypeError{rt}
 	}
 
-	iter := rv.MapRange()
-	for iter.Next() {
-		if err := e.encodeByType(e.makeMapKey(key, iter.Key().String()), iter.Value()); err != nil {
+	// sort the keys so that the output is stable when the order is preserved
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	for _, k := range keys {
+		if err := e.encodeByType(e.makeMapKey(key, k.String()), rv.MapIndex(k), opt); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (e *encoder) encodeArray(key string, rv reflect.Value) error {
+func (e *encoder) encodeOrderedMap(key string, rv reflect.Value, opt tagOption) error {
+	q := rv.Interface().(OrderedQ)
+	if q.Len() == 0 {
+		if key != "" {
+			e.add(key, defaultNilValue)
+		}
+		return nil
+	}
+
+	// keep the order of the nested OrderedQ in the sorted output
+	if key != "" && e.sortKeys == nil {
+		e.sortKeys = make(map[string]string)
+	}
+	prefix := e.sortKey(key)
+
+	var err error
+	i := 0
+	q.Range(func(k string, v interface{}) bool {
+		ck := e.makeMapKey(key, k)
+		if key != "" {
+			e.sortKeys[ck] = fmt.Sprintf("%s[%010d]", prefix, i)
+		}
+		i++
+
+		// pass the value as interface kind, same as the values of Q
+		err = e.encodeByType(ck, reflect.ValueOf(&v).Elem(), opt)
+		return err == nil
+	})
+	return err
+}
+
+func (e *encoder) encodeArray(key string, rv reflect.Value, opt tagOption) error {
 	if rv.Len() == 0 {
-		e.v.Add(key, defaultNilValue)
+		e.add(key, defaultNilValue)
 		return nil
 	}
 
 	for i := 0; i < rv.Len(); i++ {
 		k := key + "[" + strconv.Itoa(i) + "]"
-		if err := e.encodeByType(k, rv.Index(i)); err != nil {
+		if err := e.encodeByType(k, rv.Index(i), opt); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (e *encoder) encodeSlice(key string, rv reflect.Value) error {
+func (e *encoder) encodeSlice(key string, rv reflect.Value, opt tagOption) error {
 	if rv.IsNil() {
-		e.v.Add(key, defaultNilValue)
+		e.add(key, defaultNilValue)
 		return nil
 	}
-	return e.encodeArray(key, rv)
+	return e.encodeArray(key, rv, opt)
 }
 
 func (e *encoder) encodeStruct(key string, rv reflect.Value) error {
 	for i := 0; i < rv.NumField(); i++ {
 		f := rv.Type().Field(i)
-		if !f.IsExported() {
-			continue
+		tag, opt := e.config.fieldTag(f)
+		if opt.invalid != "" {
+			return &invalidTagOptionError{rv.Type(), f.Name, opt.invalid}
 		}
+		frv := rv.Field(i)
 
-		tag, opt := parseTag(f.Tag)
+		if opt.inline {
+			if err := e.encodeInline(key, frv); err != nil {
+				return err
+			}
+			continue
+		}
 		if tag == "" {
 			continue
 		}
 
-		frv := rv.FieldByName(f.Name)
 		if opt.omitempty && isEmptyValue(frv) {
 			continue
 		}
 
-		if err := e.encodeByType(e.makeMapKey(key, tag), frv); err != nil {
+		if opt.sealed {
+			if err := e.encodeSealed(e.makeMapKey(key, tag), tag, frv, opt); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := e.encodeByType(e.makeMapKey(key, tag), frv, opt); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// encodeInline encodes the fields of the struct as the fields of the parent
+func (e *encoder) encodeInline(key string, rv reflect.Value) error {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return &unsupportedTypeError{rv.Type()}
+	}
+	return e.encodeStruct(key, rv)
+}
+
+// encodeSealed encrypts the value of the field, only a single value can be sealed
+func (e *encoder) encodeSealed(key, tag string, rv reflect.Value, opt tagOption) error {
+	sub := encoder{config: e.config, v: make(url.Values)}
+	if err := sub.encodeByType(tag, rv, opt); err != nil {
+		return err
+	}
+	if len(sub.v) != 1 || len(sub.v[tag]) != 1 {
+		return &unsupportedTypeError{rv.Type()}
+	}
+
+	// authenticate the full key so that the value cannot be moved to another key
+	v, err := e.config.seal(key, sub.v[tag][0])
+	if err != nil {
+		return err
+	}
+	e.add(key, v)
+	return nil
+}
+
 func (e *encoder) makeMapKey(key, ch string) string {
 	if key == "" {
 		return ch
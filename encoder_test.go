@@ -1,7 +1,9 @@
 package qstring_test
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"testing"
 	"unsafe"
@@ -1039,11 +1041,126 @@ func runEncodeTest(t *testing.T, testCases []encodeCase) {
 				t.Errorf("Encode() should returns error, want %q", tc.err)
 			}
 
-			a := strings.ReplaceAll(actual, "%5B", "[")
-			a = strings.ReplaceAll(a, "%5D", "]")
+			a := unescapeBrackets(actual)
 			if a != tc.expected {
 				t.Errorf("Encode() returns \n%q\nwant \n%q", a, tc.expected)
 			}
 		})
 	}
 }
+
+func unescapeBrackets(s string) string {
+	s = strings.ReplaceAll(s, "%5B", "[")
+	return strings.ReplaceAll(s, "%5D", "]")
+}
+
+func TestEncodeURL(t *testing.T) {
+	type s struct {
+		Page int      `qstring:"page"`
+		Sort string   `qstring:"sort,omitempty"`
+		Tags []string `qstring:"tags,omitempty"`
+	}
+
+	base := "https://example.com/items?sort=desc&q=a%20b&page=1&page=2#top"
+
+	testCases := []struct {
+		name     string
+		base     string
+		v        interface{}
+		mode     qstring.URLMode
+		err      error
+		expected string
+	}{
+		{name: "merge", base: base, v: s{Page: 3, Tags: []string{"x"}}, mode: qstring.URLMerge,
+			expected: "https://example.com/items?sort=desc&q=a%20b&page=3&tags%5B0%5D=x#top"},
+		{name: "replace", base: base, v: s{Page: 3}, mode: qstring.URLReplace,
+			expected: "https://example.com/items?page=3#top"},
+		{name: "append", base: base, v: s{Page: 3, Sort: "asc"}, mode: qstring.URLAppend,
+			expected: "https://example.com/items?sort=desc&q=a%20b&page=1&page=2&page=3&sort=asc#top"},
+		{name: "no query", base: "https://example.com/items", v: qstring.Q{"b": "1", "a": "2"}, mode: qstring.URLMerge,
+			expected: "https://example.com/items?a=2&b=1"},
+		{name: "string keeps order", base: "https://example.com/?a=1", v: "c=3&b=2", mode: qstring.URLMerge,
+			expected: "https://example.com/?a=1&c=3&b=2"},
+		{name: "unsupported", base: base, v: 1, mode: qstring.URLMerge, err: fmt.Errorf("int is not supported")},
+		{name: "invalid query", base: "https://example.com/?%zz=1", v: s{}, mode: qstring.URLMerge, err: errors.New(`invalid URL escape "%zz"`)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := qstring.EncodeURL(u, tc.v, tc.mode)
+			if err != nil {
+				assertResult(t, "EncodeURL()", nil, err, nil, tc.err)
+				return
+			}
+			assertResult(t, "EncodeURL()", actual.String(), err, tc.expected, tc.err)
+			if u.String() != tc.base {
+				t.Errorf("EncodeURL() should not modify base, got %q", u.String())
+			}
+		})
+	}
+
+	t.Run("nil base", func(t *testing.T) {
+		_, err := qstring.EncodeURL(nil, s{}, qstring.URLMerge)
+		assertResult(t, "EncodeURL()", nil, err, nil, fmt.Errorf("nil ptr is not supported"))
+	})
+}
+
+func TestEncoder_escaping(t *testing.T) {
+	q := qstring.Q{"key": qstring.Q{"a b": "x y*~"}}
+
+	testCases := []struct {
+		name     string
+		escaping qstring.Escaping
+		v        interface{}
+		expected string
+	}{
+		{name: "form", escaping: qstring.EscapeForm, v: q, expected: "key%5Ba+b%5D=x+y%2A~"},
+		{name: "rfc3986", escaping: qstring.EscapeRFC3986, v: q, expected: "key%5Ba%20b%5D=x%20y%2A~"},
+		{name: "readable brackets", escaping: qstring.EscapeReadableBrackets, v: q, expected: "key[a+b]=x+y%2A~"},
+		{name: "form string", escaping: qstring.EscapeForm, v: "?key[a]=x y&b=1=2&c", expected: "?key%5Ba%5D=x+y&b=1=2&c"},
+		{name: "rfc3986 string", escaping: qstring.EscapeRFC3986, v: "key[a]=x y", expected: "key%5Ba%5D=x%20y"},
+		{name: "readable brackets string", escaping: qstring.EscapeReadableBrackets, v: "key[a]=[x]", expected: "key[a]=%5Bx%5D"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := qstring.NewEncoder(qstring.WithEscaping(tc.escaping)).Encode(tc.v)
+			assertResult(t, "Encode()", actual, err, tc.expected, nil)
+		})
+	}
+
+	t.Run("url", func(t *testing.T) {
+		u, _ := url.Parse("https://example.com/?a=1")
+		actual, err := qstring.NewEncoder(qstring.WithEscaping(qstring.EscapeReadableBrackets)).EncodeURL(u, q, qstring.URLMerge)
+		if err != nil {
+			t.Fatalf("EncodeURL() should not returns error, got %q", err)
+		}
+		assertResult(t, "EncodeURL()", actual.String(), nil, "https://example.com/?a=1&key[a+b]=x+y%2A~", nil)
+	})
+}
+
+func TestEncode_bytes(t *testing.T) {
+	type s struct {
+		Data  []byte   `qstring:"data,omitempty"`
+		Hex   []byte   `qstring:"hex,hex,omitempty"`
+		Std   []byte   `qstring:"std,base64std,omitempty"`
+		Nil   []byte   `qstring:"nil"`
+		Multi [][]byte `qstring:"multi,hex,omitempty"`
+	}
+
+	runEncodeTest(t, []encodeCase{
+		{name: "base64url", q: s{Data: []byte("hi??>>")}, expected: "data=aGk_Pz4-&nil="},
+		{name: "hex", q: s{Hex: []byte("hi")}, expected: "hex=6869&nil="},
+		{name: "base64std", q: s{Std: []byte("hi??>>")}, expected: "nil=&std=aGk%2FPz4%2B"},
+		{name: "array", q: struct {
+			Hash [2]byte `qstring:"hash,hex"`
+		}{Hash: [2]byte{1, 255}}, expected: "hash=01ff"},
+		{name: "slice of bytes", q: s{Multi: [][]byte{{1}, {2}}}, expected: "multi[0]=01&multi[1]=02&nil="},
+		{name: "map value", q: qstring.Q{"key": []byte("hi")}, expected: "key=aGk"},
+	})
+}
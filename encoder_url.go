@@ -0,0 +1,103 @@
+package qstring
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// URLMode is the way to combine the existing query of the URL with the encoded value.
+type URLMode int
+
+const (
+	// URLMerge replaces the existing parameters that have the same key as the encoded value,
+	// and keeps the others.
+	URLMerge URLMode = iota
+	// URLReplace discards the existing query.
+	URLReplace
+	// URLAppend appends the encoded value after the existing query.
+	URLAppend
+)
+
+// rawParam is a parameter of the existing query
+type rawParam struct {
+	// key is the unescaped key
+	key string
+	// raw is the "key=value" as written in the query
+	raw string
+}
+
+// EncodeURL returns a new URL whose query is the combination of
+// the query of base and the encoded value.
+//
+// The value supports the same types as Encode.
+// The order of the existing parameters and the fragment of base are preserved.
+// The existing parameters are compared with the encoded value by the raw key (e.g. "key[a]").
+func EncodeURL(base *url.URL, v interface{}, mode URLMode) (*url.URL, error) {
+	return NewEncoder().EncodeURL(base, v, mode)
+}
+
+// EncodeURL returns a new URL like EncodeURL.
+func (enc *Encoder) EncodeURL(base *url.URL, v interface{}, mode URLMode) (*url.URL, error) {
+	if base == nil {
+		return nil, &invalidEncodeError{reflect.TypeOf(base)}
+	}
+
+	e := encoder{config: enc.config}
+	values, err := e.values(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing []rawParam
+	if mode != URLReplace {
+		existing, err = parseRawParams(base.RawQuery)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	params := make([]string, 0, len(existing)+len(values))
+	replaced := make(map[string]bool)
+
+	for _, p := range existing {
+		if _, ok := values[p.key]; !ok || mode != URLMerge {
+			params = append(params, p.raw)
+			continue
+		}
+		// replace at the position of the first occurrence
+		if !replaced[p.key] {
+			params = append(params, e.config.encodeParams(p.key, values[p.key])...)
+			replaced[p.key] = true
+		}
+	}
+
+	for _, k := range e.orderedKeys() {
+		if !replaced[k] {
+			params = append(params, e.config.encodeParams(k, values[k])...)
+		}
+	}
+
+	u := *base
+	u.RawQuery = strings.Join(params, "&")
+	return &u, nil
+}
+
+func parseRawParams(query string) ([]rawParam, error) {
+	var params []rawParam
+	for _, raw := range strings.Split(query, "&") {
+		if raw == "" {
+			continue
+		}
+		key := raw
+		if i := strings.Index(key, "="); i >= 0 {
+			key = key[:i]
+		}
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, rawParam{key: key, raw: raw})
+	}
+	return params, nil
+}
@@ -1,8 +1,10 @@
 package qstring
 
 import (
+	"errors"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 // unsupportedTypeError is an error for unsupported types
@@ -60,6 +62,17 @@ func (e *arrayIndexOutOfRangeDecodeError) Error() string {
 	return "index out of range [" + strconv.Itoa(e.len) + "] with " + e.rt.String()
 }
 
+// invalidTagOptionError is an error for the option of the tag that cannot be parsed
+type invalidTagOptionError struct {
+	rt     reflect.Type
+	field  string
+	option string
+}
+
+func (e *invalidTagOptionError) Error() string {
+	return `invalid tag option "` + e.option + `" of ` + e.rt.String() + "." + e.field
+}
+
 // noAssignableValueError is an error
 // if the value cannot be assigned to a structure
 type noAssignableValueError struct {
@@ -79,3 +92,159 @@ type multipleKeysError struct{}
 func (e *multipleKeysError) Error() string {
 	return "cannot decode due to multiple keys"
 }
+
+// invalidPathError is an error when the key path cannot be parsed
+type invalidPathError struct {
+	path string
+}
+
+func (e *invalidPathError) Error() string {
+	return `invalid path "` + e.path + `"`
+}
+
+// pathNotFoundError is an error when the key path does not exist
+type pathNotFoundError struct {
+	path string
+}
+
+func (e *pathNotFoundError) Error() string {
+	return `"` + e.path + `" is not found`
+}
+
+// pathTypeError is an error
+// when the value on the key path is not the expected type
+type pathTypeError struct {
+	path     string
+	rt       reflect.Type
+	expected string
+}
+
+func (e *pathTypeError) Error() string {
+	t := "nil"
+	if e.rt != nil {
+		t = e.rt.String()
+	}
+	return `"` + e.path + `" is ` + t + `, not ` + e.expected
+}
+
+// ambiguousKeyError is an error when the key matches several keys
+// with the case-insensitive or normalized matching
+type ambiguousKeyError struct {
+	key     string
+	matches []string
+}
+
+func (e *ambiguousKeyError) Error() string {
+	return `"` + e.key + `" is ambiguous between "` + strings.Join(e.matches, `", "`) + `"`
+}
+
+// FieldError is an error when decoding the value of the key.
+type FieldError struct {
+	// Key is the key of the query string in bracket form (e.g. "filter[items][0][price]").
+	Key string
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors is the list of FieldError.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Key+": "+fe.Error())
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// Unwrap returns the errors of the list.
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, fe := range e {
+		errs = append(errs, fe)
+	}
+	return errs
+}
+
+// Is reports whether any error of the list matches target.
+// It is called by errors.Is before Go 1.20, which does not follow Unwrap() []error.
+func (e FieldErrors) Is(target error) bool {
+	for _, fe := range e {
+		if errors.Is(fe, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of the list that matches target.
+// It is called by errors.As before Go 1.20, which does not follow Unwrap() []error.
+func (e FieldErrors) As(target interface{}) bool {
+	for _, fe := range e {
+		if errors.As(fe, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClaimError is returned by DecodeInto when the keys of the query string
+// are claimed by more than one target, or by none with WithDisallowUnclaimedKeys.
+type ClaimError struct {
+	// Conflicts is the sorted keys claimed by more than one target
+	Conflicts []string
+	// Unclaimed is the sorted keys claimed by no target
+	Unclaimed []string
+}
+
+func (e *ClaimError) Error() string {
+	msgs := make([]string, 0, 2)
+	if len(e.Conflicts) > 0 {
+		msgs = append(msgs, "keys claimed by more than one target: "+strings.Join(e.Conflicts, ", "))
+	}
+	if len(e.Unclaimed) > 0 {
+		msgs = append(msgs, "keys claimed by no target: "+strings.Join(e.Unclaimed, ", "))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// wrapFieldError prepends the key to the key of the field errors
+func wrapFieldError(key string, err error) error {
+	switch e := err.(type) {
+	case *FieldError:
+		return &FieldError{Key: joinFieldKey(key, e.Key), Err: e.Err}
+	case FieldErrors:
+		errs := make(FieldErrors, 0, len(e))
+		for _, fe := range e {
+			errs = append(errs, &FieldError{Key: joinFieldKey(key, fe.Key), Err: fe.Err})
+		}
+		return errs
+	}
+	return &FieldError{Key: key, Err: err}
+}
+
+func appendFieldErrors(errs FieldErrors, err error) FieldErrors {
+	switch e := err.(type) {
+	case *FieldError:
+		return append(errs, e)
+	case FieldErrors:
+		return append(errs, e...)
+	}
+	return append(errs, &FieldError{Err: err})
+}
+
+// joinFieldKey converts "a" and "b[c]" to "a[b][c]"
+func joinFieldKey(key, child string) string {
+	if idx := strings.Index(child, "["); idx != -1 {
+		return key + "[" + child[:idx] + "]" + child[idx:]
+	}
+	return key + "[" + child + "]"
+}
@@ -0,0 +1,87 @@
+package qstring
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Escaping is the profile of percent-encoding used by Encoder.
+type Escaping int
+
+const (
+	// EscapeForm escapes like application/x-www-form-urlencoded (url.QueryEscape).
+	// A space is encoded as "+".
+	EscapeForm Escaping = iota
+	// EscapeRFC3986 escapes all characters except the unreserved characters of RFC 3986.
+	// A space is encoded as "%20".
+	EscapeRFC3986
+	// EscapeReadableBrackets escapes like EscapeForm, but leaves "[" and "]" of the keys unescaped
+	// (e.g. "key[a]=1").
+	EscapeReadableBrackets
+)
+
+// WithEscaping sets the profile of percent-encoding.
+// The default is EscapeForm.
+func WithEscaping(p Escaping) Option {
+	return func(c *config) {
+		c.escaping = p
+	}
+}
+
+var readableBracketsReplacer = strings.NewReplacer("%5B", "[", "%5D", "]")
+
+func (c *config) escapeKey(s string) string {
+	switch c.escaping {
+	case EscapeRFC3986:
+		return escapeRFC3986(s)
+	case EscapeReadableBrackets:
+		return readableBracketsReplacer.Replace(url.QueryEscape(s))
+	}
+	return url.QueryEscape(s)
+}
+
+func (c *config) escapeValue(s string) string {
+	if c.escaping == EscapeRFC3986 {
+		return escapeRFC3986(s)
+	}
+	return url.QueryEscape(s)
+}
+
+// encodeParams returns the escaped "key=value" pairs
+func (c *config) encodeParams(key string, values []string) []string {
+	keyEscaped := c.escapeKey(key)
+	params := make([]string, 0, len(values))
+	for _, v := range values {
+		params = append(params, keyEscaped+"="+c.escapeValue(v))
+	}
+	return params
+}
+
+// escapeRFC3986 percent-encodes all characters except the unreserved characters of RFC 3986
+func escapeRFC3986(s string) string {
+	const upperhex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isUnreserved(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(upperhex[c>>4])
+		b.WriteByte(upperhex[c&15])
+	}
+	return b.String()
+}
+
+func isUnreserved(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	case c == '-', c == '.', c == '_', c == '~':
+		return true
+	}
+	return false
+}
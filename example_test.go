@@ -1,7 +1,12 @@
 package qstring_test
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
 
 	"github.com/masakurapa/qstring"
 )
@@ -118,3 +123,57 @@ func ExampleDecodeToSlice() {
 
 	// Output: [a b c]
 }
+
+func ExampleDecodeToOrderedMap() {
+	q, _ := qstring.DecodeToOrderedMap("key%5Bb%5D=1&key%5Ba%5D=2&c=3")
+	fmt.Println(q.Keys())
+
+	s, _ := qstring.Encode(q)
+	fmt.Println(s)
+
+	// Output:
+	// [key c]
+	// key%5Bb%5D=1&key%5Ba%5D=2&c=3
+}
+
+func ExampleDecodeRequest() {
+	type req struct {
+		ID   int    `qstring:"id"`
+		Name string `qstring:"name"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/?id=1", strings.NewReader("name=foo"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	v := req{}
+	if err := qstring.DecodeRequest(r, &v); err != nil {
+		var re *qstring.RequestError
+		if errors.As(err, &re) {
+			fmt.Println(re.StatusCode())
+		}
+		return
+	}
+	fmt.Printf("%+v", v)
+
+	// Output: {ID:1 Name:foo}
+}
+
+func ExampleVerifyDecode() {
+	type download struct {
+		File string `qstring:"file"`
+	}
+	key := []byte("secret")
+
+	signed, _ := qstring.Sign(download{File: "report.pdf"}, key, time.Hour)
+
+	v := download{}
+	err := qstring.VerifyDecode(signed, key, &v)
+	fmt.Println(v.File, err)
+
+	err = qstring.VerifyDecode(strings.Replace(signed, "report", "secret", 1), key, &v)
+	fmt.Println(errors.Is(err, qstring.ErrInvalidSignature))
+
+	// Output:
+	// report.pdf <nil>
+	// true
+}
@@ -0,0 +1,126 @@
+package qstring
+
+import (
+	"reflect"
+	"sort"
+)
+
+// WithDisallowUnclaimedKeys makes DecodeInto return a ClaimError
+// if the query string has keys that are claimed by none of the targets.
+func WithDisallowUnclaimedKeys() Option {
+	return func(c *config) {
+		c.disallowUnclaimed = true
+	}
+}
+
+// DecodeInto is URL-decodes the query string into several structs with a single parse.
+//
+// Each target is a pointer to struct and receives the keys of its own tags,
+// e.g. DecodeInto(query, &pagination, &filters, &sorting).
+// If a key in the query string is claimed by more than one target,
+// it returns a ClaimError and nothing is decoded.
+func DecodeInto(s string, targets ...interface{}) error {
+	return NewDecoder().DecodeInto(s, targets...)
+}
+
+// DecodeInto is URL-decodes the query string into several structs like DecodeInto.
+// With WithDisallowUnclaimedKeys, the keys claimed by no target are also reported.
+func (dec *Decoder) DecodeInto(s string, targets ...interface{}) error {
+	d := decoder{config: dec.config, query: s}
+	return d.decodeInto(targets)
+}
+
+func (d *decoder) decodeInto(targets []interface{}) error {
+	rvs := make([]reflect.Value, 0, len(targets))
+	for _, v := range targets {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return &invalidDecodeError{reflect.TypeOf(v)}
+		}
+		if rv.Elem().Kind() != reflect.Struct || rv.Elem().Type() == orderedQType {
+			return &unsupportedTypeError{rv.Elem().Type()}
+		}
+		rvs = append(rvs, rv.Elem())
+	}
+
+	valueMap, err := d.createIntermediateStruct()
+	if err != nil {
+		return err
+	}
+
+	claims := make(map[string]int)
+	for _, rv := range rvs {
+		seen := make(map[string]bool)
+		for _, key := range d.config.structKeys(rv.Type()) {
+			if n := d.config.normalizeKey(key); !seen[n] {
+				seen[n] = true
+				claims[n]++
+			}
+		}
+	}
+
+	var claimErr ClaimError
+	for key := range valueMap {
+		switch n := claims[d.config.normalizeKey(key)]; {
+		case n > 1:
+			claimErr.Conflicts = append(claimErr.Conflicts, key)
+		case n == 0 && d.config.disallowUnclaimed:
+			claimErr.Unclaimed = append(claimErr.Unclaimed, key)
+		}
+	}
+	if len(claimErr.Conflicts) > 0 || len(claimErr.Unclaimed) > 0 {
+		sort.Strings(claimErr.Conflicts)
+		sort.Strings(claimErr.Unclaimed)
+		return &claimErr
+	}
+
+	for _, rv := range rvs {
+		if err := d.setStruct(rv, valueMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structKeys returns the top-level keys the struct decodes, duplicates are removed
+func (c *config) structKeys(rt reflect.Type) []string {
+	return c.appendStructKeys(make([]string, 0, rt.NumField()), make(map[string]bool), rt, make(map[reflect.Type]bool))
+}
+
+// appendStructKeys appends the keys of the struct, the inline struct visited is skipped
+func (c *config) appendStructKeys(keys []string, seen map[string]bool, rt reflect.Type, visited map[reflect.Type]bool) []string {
+	if visited[rt] {
+		return keys
+	}
+	visited[rt] = true
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag, opt := c.fieldTag(f)
+		if opt.inline {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				keys = c.appendStructKeys(keys, seen, ft, visited)
+			}
+			continue
+		}
+		if tag != "" {
+			keys = appendKeys(keys, seen, tag)
+			keys = appendKeys(keys, seen, opt.aliases...)
+		}
+	}
+	return keys
+}
+
+func appendKeys(keys []string, seen map[string]bool, add ...string) []string {
+	for _, key := range add {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,99 @@
+package qstring_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestDecodeInto(t *testing.T) {
+	type pagination struct {
+		Page int `qstring:"page"`
+		Size int `qstring:"size"`
+	}
+	type filters struct {
+		Status string   `qstring:"status"`
+		Tags   []string `qstring:"tags"`
+	}
+	type sorting struct {
+		Sort string `qstring:"sort"`
+	}
+	type conflicting struct {
+		Page   string `qstring:"page"`
+		Status string `qstring:"status"`
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		var p pagination
+		var f filters
+		var s sorting
+		err := qstring.DecodeInto("page=2&size=10&status=open&tags[]=a&tags[]=b&sort=name", &p, &f, &s)
+		if err != nil {
+			t.Fatalf("DecodeInto() should not returns error, got %q", err)
+		}
+
+		actual := []interface{}{p, f, s}
+		expected := []interface{}{
+			pagination{Page: 2, Size: 10},
+			filters{Status: "open", Tags: []string{"a", "b"}},
+			sorting{Sort: "name"},
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("DecodeInto() returns \n%#v\nwant \n%#v", actual, expected)
+		}
+	})
+
+	testCases := []struct {
+		name    string
+		q       string
+		dec     *qstring.Decoder
+		targets []interface{}
+		err     error
+	}{
+		{name: "conflicts", q: "page=2&status=open&sort=name", dec: qstring.NewDecoder(),
+			targets: []interface{}{&pagination{}, &filters{}, &conflicting{}},
+			err:     fmt.Errorf("keys claimed by more than one target: page, status")},
+		{name: "conflict not in query", q: "sort=name", dec: qstring.NewDecoder(),
+			targets: []interface{}{&pagination{}, &conflicting{}, &sorting{}}},
+		{name: "unclaimed allowed", q: "page=2&debug=1", dec: qstring.NewDecoder(),
+			targets: []interface{}{&pagination{}}},
+		{name: "unclaimed disallowed", q: "page=2&debug=1&trace=1", dec: qstring.NewDecoder(qstring.WithDisallowUnclaimedKeys()),
+			targets: []interface{}{&pagination{}},
+			err:     fmt.Errorf("keys claimed by no target: debug, trace")},
+		{name: "conflicts and unclaimed", q: "page=2&debug=1", dec: qstring.NewDecoder(qstring.WithDisallowUnclaimedKeys()),
+			targets: []interface{}{&pagination{}, &conflicting{}},
+			err:     fmt.Errorf("keys claimed by more than one target: page; keys claimed by no target: debug")},
+		{name: "not pointer", q: "page=2", dec: qstring.NewDecoder(),
+			targets: []interface{}{pagination{}},
+			err:     fmt.Errorf("non-pointer is not supported")},
+		{name: "not struct", q: "page=2", dec: qstring.NewDecoder(),
+			targets: []interface{}{&qstring.Q{}},
+			err:     fmt.Errorf("qstring.Q is not supported")},
+		{name: "not assign value", q: "page=x", dec: qstring.NewDecoder(),
+			targets: []interface{}{&pagination{}},
+			err:     fmt.Errorf(`"x" can not be assign to int`)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.dec.DecodeInto(tc.q, tc.targets...)
+			assertResult(t, "DecodeInto()", nil, err, nil, tc.err)
+		})
+	}
+
+	t.Run("nothing is decoded on conflicts", func(t *testing.T) {
+		var p pagination
+		err := qstring.DecodeInto("page=2&status=open", &p, &conflicting{})
+
+		var ce *qstring.ClaimError
+		if !errors.As(err, &ce) || !reflect.DeepEqual(ce.Conflicts, []string{"page"}) {
+			t.Fatalf("DecodeInto() error = %#v, want ClaimError", err)
+		}
+		if p.Page != 0 {
+			t.Errorf("DecodeInto() decoded %#v, want nothing decoded", p)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package qstring
+
+import (
+	"sort"
+	"strings"
+)
+
+// WithCaseInsensitiveKeys makes Decoder match the keys of the query string
+// to the struct fields case-insensitively (e.g. "PageSize" to `qstring:"pagesize"`).
+//
+// If several keys match the same field, or a key matches several fields,
+// Decoder returns an error instead of choosing one.
+func WithCaseInsensitiveKeys() Option {
+	return func(c *config) {
+		c.caseInsensitiveKeys = true
+	}
+}
+
+// WithKeyNormalizer sets the func applied to both the keys of the query string
+// and the keys of the struct fields before matching them (e.g. RemoveSeparators).
+// The ambiguous matches are reported as with WithCaseInsensitiveKeys.
+func WithKeyNormalizer(fn func(key string) string) Option {
+	return func(c *config) {
+		c.keyNormalizer = fn
+	}
+}
+
+var separatorsReplacer = strings.NewReplacer("_", "", "-", "")
+
+// RemoveSeparators removes "_" and "-" from the key,
+// so that "page_size", "page-size" and "pageSize" match with WithCaseInsensitiveKeys.
+func RemoveSeparators(key string) string {
+	return separatorsReplacer.Replace(key)
+}
+
+func (c *config) normalizesKeys() bool {
+	return c.caseInsensitiveKeys || c.keyNormalizer != nil
+}
+
+func (c *config) normalizeKey(key string) string {
+	if c.keyNormalizer != nil {
+		key = c.keyNormalizer(key)
+	}
+	if c.caseInsensitiveKeys {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
+// findKey returns the key of the values that matches the name of the field.
+// The second return value reports whether the key was found.
+func (c *config) findKey(uvm urlValueMap, name string) (string, bool, error) {
+	if !c.normalizesKeys() {
+		_, ok := uvm[name]
+		return name, ok, nil
+	}
+
+	n := c.normalizeKey(name)
+	var matches []string
+	for key := range uvm {
+		if c.normalizeKey(key) == n {
+			matches = append(matches, key)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return matches[0], true, nil
+	}
+	sort.Strings(matches)
+	return "", false, &ambiguousKeyError{name, matches}
+}
@@ -0,0 +1,67 @@
+package qstring_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestKeyMatching(t *testing.T) {
+	type child struct {
+		Status string `qstring:"status"`
+	}
+	type s struct {
+		PageSize int    `qstring:"page_size"`
+		Query    string `qstring:"query,alias=q"`
+		Child    child  `qstring:"child"`
+	}
+	type collision struct {
+		PageSize  int `qstring:"page_size"`
+		PageSize2 int `qstring:"pageSize"`
+	}
+
+	ci := qstring.NewDecoder(qstring.WithCaseInsensitiveKeys())
+	norm := qstring.NewDecoder(qstring.WithCaseInsensitiveKeys(), qstring.WithKeyNormalizer(qstring.RemoveSeparators))
+
+	testCases := []struct {
+		name     string
+		dec      *qstring.Decoder
+		q        string
+		v        interface{}
+		expected interface{}
+		err      error
+	}{
+		{name: "exact by default", dec: qstring.NewDecoder(), q: "PAGE_SIZE=1&page_size=2", v: &s{}, expected: &s{PageSize: 2}},
+		{name: "case-insensitive", dec: ci, q: "PAGE_SIZE=1&Q=a&CHILD[Status]=open", v: &s{},
+			expected: &s{PageSize: 1, Query: "a", Child: child{Status: "open"}}},
+		{name: "case-insensitive does not normalize", dec: ci, q: "pageSize=1", v: &s{}, expected: &s{}},
+		{name: "normalized camel case", dec: norm, q: "pageSize=1", v: &s{}, expected: &s{PageSize: 1}},
+		{name: "normalized kebab case", dec: norm, q: "Page-Size=1", v: &s{}, expected: &s{PageSize: 1}},
+		{name: "normalizer without case-insensitive", dec: qstring.NewDecoder(qstring.WithKeyNormalizer(strings.ToUpper)), q: "Page_Size=1", v: &s{},
+			expected: &s{PageSize: 1}},
+		{name: "ambiguous keys", dec: norm, q: "pageSize=1&page_size=2", v: &s{},
+			err: fmt.Errorf(`"page_size" is ambiguous between "pageSize", "page_size"`)},
+		{name: "ambiguous nested keys", dec: ci, q: "child[status]=a&child[STATUS]=b", v: &s{},
+			err: fmt.Errorf(`"status" is ambiguous between "STATUS", "status"`)},
+		{name: "ambiguous fields", dec: norm, q: "pagesize=1", v: &collision{},
+			err: fmt.Errorf(`"pagesize" is ambiguous between "page_size", "pageSize"`)},
+		{name: "fields not in query", dec: norm, q: "no=1", v: &collision{}, expected: &collision{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.dec.Decode(tc.q, tc.v)
+			assertResult(t, "Decode()", tc.v, err, tc.expected, tc.err)
+		})
+	}
+
+	t.Run("DecodeInto", func(t *testing.T) {
+		type other struct {
+			Size int `qstring:"pageSize"`
+		}
+		err := norm.DecodeInto("PageSize=1", &s{}, &other{})
+		assertResult(t, "DecodeInto()", nil, err, nil, fmt.Errorf("keys claimed by more than one target: PageSize"))
+	})
+}
@@ -0,0 +1,107 @@
+package qstring
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Limits is the limits of the query string accepted by Decoder.
+// The zero value of each field means unlimited.
+type Limits struct {
+	// MaxParams is the maximum number of the parameters.
+	MaxParams int
+	// MaxDepth is the maximum nesting depth of the key, "a[b][c]" is 2.
+	MaxDepth int
+	// MaxKeyBytes is the maximum length of the unescaped key.
+	MaxKeyBytes int
+	// MaxValueBytes is the maximum length of the unescaped value.
+	MaxValueBytes int
+	// MaxValuesPerKey is the maximum number of the values of the same key.
+	MaxValuesPerKey int
+	// MaxTotalBytes is the maximum length of the query string.
+	MaxTotalBytes int
+}
+
+// WithLimits sets the limits of the query string accepted by Decoder.
+// The limits are checked while parsing the query string, before the values are decoded,
+// and a LimitExceededError is returned if any limit is exceeded.
+func WithLimits(limits Limits) Option {
+	return func(c *config) {
+		c.limits = limits
+	}
+}
+
+// LimitExceededError is returned when the query string exceeds the Limits.
+type LimitExceededError struct {
+	// Limit is the name of the field of Limits (e.g. "MaxDepth").
+	Limit string
+	// Max is the value of the limit.
+	Max int
+	// Key is the key of the parameter, it is empty for MaxParams and MaxTotalBytes.
+	Key string
+}
+
+func (e *LimitExceededError) Error() string {
+	s := "query"
+	if e.Key != "" {
+		s = `key "` + e.Key + `"`
+	}
+	return s + " exceeds the limit of " + e.Limit + " (" + strconv.Itoa(e.Max) + ")"
+}
+
+// limitChecker counts the parameters to check the limits
+type limitChecker struct {
+	limits Limits
+	params int
+	values map[string]int
+}
+
+func newLimitChecker(limits Limits) *limitChecker {
+	return &limitChecker{limits: limits, values: make(map[string]int)}
+}
+
+func (lc *limitChecker) checkTotal(n int) error {
+	if exceeds(n, lc.limits.MaxTotalBytes) {
+		return &LimitExceededError{Limit: "MaxTotalBytes", Max: lc.limits.MaxTotalBytes}
+	}
+	return nil
+}
+
+// capacity returns the capacity of the parameters not exceeding MaxParams
+func (lc *limitChecker) capacity(n int) int {
+	if lc.limits.MaxParams > 0 && n > lc.limits.MaxParams {
+		return lc.limits.MaxParams
+	}
+	return n
+}
+
+// check counts the parameter and checks the limits
+func (lc *limitChecker) check(key, value string) error {
+	lc.params++
+	if exceeds(lc.params, lc.limits.MaxParams) {
+		return &LimitExceededError{Limit: "MaxParams", Max: lc.limits.MaxParams}
+	}
+
+	// limit the key of the error to MaxKeyBytes
+	if exceeds(len(key), lc.limits.MaxKeyBytes) {
+		return &LimitExceededError{Limit: "MaxKeyBytes", Max: lc.limits.MaxKeyBytes, Key: key[:lc.limits.MaxKeyBytes]}
+	}
+	if exceeds(strings.Count(key, "["), lc.limits.MaxDepth) {
+		return &LimitExceededError{Limit: "MaxDepth", Max: lc.limits.MaxDepth, Key: key}
+	}
+	if exceeds(len(value), lc.limits.MaxValueBytes) {
+		return &LimitExceededError{Limit: "MaxValueBytes", Max: lc.limits.MaxValueBytes, Key: key}
+	}
+
+	if lc.limits.MaxValuesPerKey > 0 {
+		lc.values[key]++
+		if exceeds(lc.values[key], lc.limits.MaxValuesPerKey) {
+			return &LimitExceededError{Limit: "MaxValuesPerKey", Max: lc.limits.MaxValuesPerKey, Key: key}
+		}
+	}
+	return nil
+}
+
+func exceeds(n, max int) bool {
+	return max > 0 && n > max
+}
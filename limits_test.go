@@ -0,0 +1,72 @@
+package qstring_test
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestWithLimits(t *testing.T) {
+	testCases := []struct {
+		name   string
+		limits qstring.Limits
+		q      string
+		err    error
+	}{
+		{name: "unlimited", limits: qstring.Limits{}, q: "a[b][c][d]=1&a[b][c][e]=2&x=" + strings.Repeat("v", 1000)},
+		{name: "max params", limits: qstring.Limits{MaxParams: 2}, q: "a=1&b=2&c=3",
+			err: fmt.Errorf("query exceeds the limit of MaxParams (2)")},
+		{name: "max params not exceeded", limits: qstring.Limits{MaxParams: 2}, q: "a=1&&b=2&"},
+		{name: "max depth", limits: qstring.Limits{MaxDepth: 2}, q: "a[b]=1&a[b][c][d]=2",
+			err: fmt.Errorf(`key "a[b][c][d]" exceeds the limit of MaxDepth (2)`)},
+		{name: "max depth not exceeded", limits: qstring.Limits{MaxDepth: 2}, q: "a[b][]=1"},
+		{name: "max depth escaped", limits: qstring.Limits{MaxDepth: 1}, q: "a%5Bb%5D%5Bc%5D=1",
+			err: fmt.Errorf(`key "a[b][c]" exceeds the limit of MaxDepth (1)`)},
+		{name: "max key bytes", limits: qstring.Limits{MaxKeyBytes: 4}, q: "abcd=1&abcdefgh=2",
+			err: fmt.Errorf(`key "abcd" exceeds the limit of MaxKeyBytes (4)`)},
+		{name: "max value bytes", limits: qstring.Limits{MaxValueBytes: 3}, q: "a=abc&b=abcd",
+			err: fmt.Errorf(`key "b" exceeds the limit of MaxValueBytes (3)`)},
+		{name: "max value bytes unescaped", limits: qstring.Limits{MaxValueBytes: 3}, q: "a=%E3%81%82"},
+		{name: "max values per key", limits: qstring.Limits{MaxValuesPerKey: 2}, q: "a[]=1&b=1&a[]=2&a[]=3",
+			err: fmt.Errorf(`key "a[]" exceeds the limit of MaxValuesPerKey (2)`)},
+		{name: "max total bytes", limits: qstring.Limits{MaxTotalBytes: 8}, q: "a=1&b=234",
+			err: fmt.Errorf("query exceeds the limit of MaxTotalBytes (8)")},
+		{name: "max total bytes not exceeded", limits: qstring.Limits{MaxTotalBytes: 8}, q: "a=1&b=23"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var q qstring.Q
+			err := qstring.NewDecoder(qstring.WithLimits(tc.limits)).Decode(tc.q, &q)
+			assertResult(t, "Decode()", nil, err, nil, tc.err)
+		})
+	}
+
+	t.Run("url.Values", func(t *testing.T) {
+		dec := qstring.NewDecoder(qstring.WithLimits(qstring.Limits{MaxValuesPerKey: 1}))
+		var q qstring.Q
+		err := dec.DecodeValues(url.Values{"a": {"1"}, "b": {"1", "2"}}, &q)
+		assertResult(t, "DecodeValues()", nil, err, nil, fmt.Errorf(`key "b" exceeds the limit of MaxValuesPerKey (1)`))
+
+		dec = qstring.NewDecoder(qstring.WithLimits(qstring.Limits{MaxTotalBytes: 7}))
+		err = dec.DecodeValues(url.Values{"a": {"1"}, "b": {"23"}}, &q)
+		assertResult(t, "DecodeValues()", nil, err, nil, fmt.Errorf("query exceeds the limit of MaxTotalBytes (7)"))
+	})
+
+	t.Run("error type", func(t *testing.T) {
+		type s struct {
+			A []string `qstring:"a"`
+		}
+		dec := qstring.NewDecoder(qstring.WithLimits(qstring.Limits{MaxParams: 1}))
+		err := dec.Decode("a[]=1&a[]=2", &s{})
+
+		var le *qstring.LimitExceededError
+		if !errors.As(err, &le) || le.Limit != "MaxParams" || le.Max != 1 {
+			t.Errorf("Decode() error = %#v, want LimitExceededError", err)
+		}
+	})
+}
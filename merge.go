@@ -0,0 +1,260 @@
+package qstring
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// MergeStrategy is the strategy when both Q have a value on the same key.
+type MergeStrategy int
+
+const (
+	// MergeReplace replaces the value with the value of the other.
+	MergeReplace MergeStrategy = iota
+	// MergeAppendList appends the value of the other to the list.
+	// A string is treated as a list with one element.
+	MergeAppendList
+	// MergeKeepExisting keeps the existing value.
+	MergeKeepExisting
+)
+
+// Difference is the result of Diff.
+//
+// Each field has the key paths in dot form (e.g. "filter.items.0.price"),
+// which can be passed to Q.Get.
+type Difference struct {
+	// Added is the key paths that exist only in b.
+	Added []string
+	// Removed is the key paths that exist only in a.
+	Removed []string
+	// Changed is the key paths whose values are different.
+	Changed []string
+}
+
+// IsEmpty reports whether there is no difference.
+func (d Difference) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Merge returns a new Q that deep merges other into q.
+//
+// Nested Q are always merged recursively.
+// Other values are merged according to the strategy.
+// Neither q nor other is modified.
+func (q Q) Merge(other Q, strategy MergeStrategy) Q {
+	merged := copyValue(q).(Q)
+	if merged == nil {
+		merged = Q{}
+	}
+	for k, v := range other {
+		cur, ok := merged[k]
+		if !ok {
+			merged[k] = copyValue(v)
+			continue
+		}
+		merged[k] = mergeValue(cur, v, strategy)
+	}
+	return merged
+}
+
+func mergeValue(cur, v interface{}, strategy MergeStrategy) interface{} {
+	cq, cok := normalizePathValue(cur).(Q)
+	vq, vok := normalizePathValue(v).(Q)
+	if cok && vok {
+		return cq.Merge(vq, strategy)
+	}
+
+	switch strategy {
+	case MergeKeepExisting:
+		return cur
+	case MergeAppendList:
+		if l, ok := appendList(cur, v); ok {
+			return l
+		}
+	}
+	return copyValue(v)
+}
+
+// appendList appends v to cur if both are a string or a list
+func appendList(cur, v interface{}) (interface{}, bool) {
+	cs, cok := toStringList(cur)
+	vs, vok := toStringList(v)
+	if cok && vok {
+		return append(cs, vs...), true
+	}
+
+	var s S
+	for _, e := range []interface{}{cur, v} {
+		switch ev := normalizePathValue(e).(type) {
+		case string:
+			s = append(s, ev)
+		case []string:
+			for _, str := range ev {
+				s = append(s, str)
+			}
+		case S:
+			for _, sv := range ev {
+				s = append(s, copyValue(sv))
+			}
+		default:
+			return nil, false
+		}
+	}
+	return s, true
+}
+
+func toStringList(v interface{}) ([]string, bool) {
+	switch vv := v.(type) {
+	case string:
+		return []string{vv}, true
+	case []string:
+		return append([]string{}, vv...), true
+	}
+	return nil, false
+}
+
+// copyValue deep copies Q, S and []string
+func copyValue(v interface{}) interface{} {
+	switch vv := normalizePathValue(v).(type) {
+	case Q:
+		if vv == nil {
+			return vv
+		}
+		q := make(Q, len(vv))
+		for k, e := range vv {
+			q[k] = copyValue(e)
+		}
+		return q
+	case S:
+		if vv == nil {
+			return vv
+		}
+		s := make(S, len(vv))
+		for i, e := range vv {
+			s[i] = copyValue(e)
+		}
+		return s
+	case []string:
+		if vv == nil {
+			return vv
+		}
+		return append([]string{}, vv...)
+	}
+	return v
+}
+
+// Diff returns the difference between a and b.
+//
+// Q are compared recursively by key, S are compared recursively by index.
+// Other values are compared as a whole.
+func Diff(a, b Q) Difference {
+	d := Difference{}
+	diffValue(&d, nil, a, b)
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+	return d
+}
+
+func diffValue(d *Difference, keys []string, a, b interface{}) {
+	switch av := normalizePathValue(a).(type) {
+	case Q:
+		bv, ok := normalizePathValue(b).(Q)
+		if !ok {
+			break
+		}
+		for k, ae := range av {
+			ck := appendKey(keys, k)
+			be, ok := bv[k]
+			if !ok {
+				d.Removed = append(d.Removed, joinPath(ck))
+				continue
+			}
+			diffValue(d, ck, ae, be)
+		}
+		for k := range bv {
+			if _, ok := av[k]; !ok {
+				d.Added = append(d.Added, joinPath(appendKey(keys, k)))
+			}
+		}
+		return
+	case S:
+		bv, ok := normalizePathValue(b).(S)
+		if !ok {
+			break
+		}
+		for i, ae := range av {
+			ck := appendKey(keys, strconv.Itoa(i))
+			if i >= len(bv) {
+				d.Removed = append(d.Removed, joinPath(ck))
+				continue
+			}
+			diffValue(d, ck, ae, bv[i])
+		}
+		for i := len(av); i < len(bv); i++ {
+			d.Added = append(d.Added, joinPath(appendKey(keys, strconv.Itoa(i))))
+		}
+		return
+	}
+
+	if !valueEqual(a, b) {
+		d.Changed = append(d.Changed, joinPath(keys))
+	}
+}
+
+func appendKey(keys []string, k string) []string {
+	ret := make([]string, len(keys), len(keys)+1)
+	copy(ret, keys)
+	return append(ret, k)
+}
+
+// Equal reports whether a and b are semantically equal.
+//
+// The order of the keys of Q is ignored, but the order of lists is respected.
+func Equal(a, b Q) bool {
+	return valueEqual(a, b)
+}
+
+func valueEqual(a, b interface{}) bool {
+	switch av := normalizePathValue(a).(type) {
+	case Q:
+		bv, ok := normalizePathValue(b).(Q)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, ae := range av {
+			be, ok := bv[k]
+			if !ok || !valueEqual(ae, be) {
+				return false
+			}
+		}
+		return true
+	case S:
+		bv, ok := normalizePathValue(b).(S)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !valueEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case []string:
+		bv, ok := b.([]string)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if av[i] != bv[i] {
+				return false
+			}
+		}
+		return true
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,131 @@
+package qstring_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestQ_Merge(t *testing.T) {
+	base := func() qstring.Q {
+		return qstring.Q{
+			"page":   "1",
+			"tags":   []string{"a"},
+			"list":   qstring.S{qstring.Q{"a": "1"}},
+			"filter": qstring.Q{"name": "x", "kind": "k"},
+		}
+	}
+	other := qstring.Q{
+		"page":   "2",
+		"tags":   "b",
+		"list":   qstring.S{"z"},
+		"filter": qstring.Q{"name": "y", "price": "100"},
+		"sort":   "asc",
+	}
+
+	testCases := []struct {
+		name     string
+		strategy qstring.MergeStrategy
+		expected qstring.Q
+	}{
+		{name: "replace", strategy: qstring.MergeReplace, expected: qstring.Q{
+			"page":   "2",
+			"tags":   "b",
+			"list":   qstring.S{"z"},
+			"filter": qstring.Q{"name": "y", "kind": "k", "price": "100"},
+			"sort":   "asc",
+		}},
+		{name: "append list", strategy: qstring.MergeAppendList, expected: qstring.Q{
+			"page":   []string{"1", "2"},
+			"tags":   []string{"a", "b"},
+			"list":   qstring.S{qstring.Q{"a": "1"}, "z"},
+			"filter": qstring.Q{"name": []string{"x", "y"}, "kind": "k", "price": "100"},
+			"sort":   "asc",
+		}},
+		{name: "keep existing", strategy: qstring.MergeKeepExisting, expected: qstring.Q{
+			"page":   "1",
+			"tags":   []string{"a"},
+			"list":   qstring.S{qstring.Q{"a": "1"}},
+			"filter": qstring.Q{"name": "x", "kind": "k", "price": "100"},
+			"sort":   "asc",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := base()
+			actual := q.Merge(other, tc.strategy)
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("Merge() returns \n%#v\nwant \n%#v", actual, tc.expected)
+			}
+			if !reflect.DeepEqual(q, base()) {
+				t.Errorf("Merge() should not modify the receiver, got \n%#v", q)
+			}
+		})
+	}
+
+	t.Run("nil receiver", func(t *testing.T) {
+		var q qstring.Q
+		actual := q.Merge(qstring.Q{"a": "1"}, qstring.MergeReplace)
+		if !reflect.DeepEqual(actual, qstring.Q{"a": "1"}) {
+			t.Errorf("Merge() returns %#v, want %#v", actual, qstring.Q{"a": "1"})
+		}
+	})
+}
+
+func TestDiff(t *testing.T) {
+	a := qstring.Q{
+		"page":   "1",
+		"tags":   []string{"a", "b"},
+		"list":   qstring.S{qstring.Q{"price": "1"}, "x"},
+		"filter": qstring.Q{"name": "x", "kind": "k"},
+	}
+	b := qstring.Q{
+		"tags":   []string{"b", "a"},
+		"list":   qstring.S{qstring.Q{"price": "2"}, "x", "y"},
+		"filter": qstring.Q{"name": "x", "price": "100"},
+		"sort":   "asc",
+	}
+
+	expected := qstring.Difference{
+		Added:   []string{"filter.price", "list.2", "sort"},
+		Removed: []string{"filter.kind", "page"},
+		Changed: []string{"list.0.price", "tags"},
+	}
+	actual := qstring.Diff(a, b)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Diff() returns \n%#v\nwant \n%#v", actual, expected)
+	}
+	if actual.IsEmpty() {
+		t.Errorf("IsEmpty() should returns false")
+	}
+	if d := qstring.Diff(a, a); !d.IsEmpty() {
+		t.Errorf("Diff() returns %#v, want empty", d)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        qstring.Q
+		b        qstring.Q
+		expected bool
+	}{
+		{name: "same", a: qstring.Q{"a": "1", "b": qstring.Q{"c": []string{"1", "2"}}}, b: qstring.Q{"b": qstring.Q{"c": []string{"1", "2"}}, "a": "1"}, expected: true},
+		{name: "unnamed types", a: qstring.Q{"a": qstring.Q{"b": qstring.S{"1"}}}, b: qstring.Q{"a": map[string]interface{}{"b": []interface{}{"1"}}}, expected: true},
+		{name: "list order", a: qstring.Q{"a": []string{"1", "2"}}, b: qstring.Q{"a": []string{"2", "1"}}, expected: false},
+		{name: "slice order", a: qstring.Q{"a": qstring.S{"1", "2"}}, b: qstring.Q{"a": qstring.S{"2", "1"}}, expected: false},
+		{name: "string and list", a: qstring.Q{"a": "1"}, b: qstring.Q{"a": []string{"1"}}, expected: false},
+		{name: "missing key", a: qstring.Q{"a": "1"}, b: qstring.Q{"a": "1", "b": "2"}, expected: false},
+		{name: "nil and empty", a: nil, b: qstring.Q{}, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := qstring.Equal(tc.a, tc.b); actual != tc.expected {
+				t.Errorf("Equal() returns %v, want %v", actual, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package qstring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Validator is the interface implemented by the types that validate themselves after decoding.
+//
+// Middleware calls Validate of the decoded value.
+// The returned *FieldError and FieldErrors are reported with their keys.
+type Validator interface {
+	Validate() error
+}
+
+// MiddlewareOption is an option for Middleware.
+type MiddlewareOption func(*middlewareConfig)
+
+// ErrorHandler responds to the request that failed decoding or validation.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+type middlewareConfig struct {
+	errorHandler ErrorHandler
+	decoder      *Decoder
+}
+
+// WithErrorHandler sets the handler that responds to the request that failed decoding or validation.
+// The default is DefaultErrorHandler.
+func WithErrorHandler(h ErrorHandler) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.errorHandler = h
+	}
+}
+
+// WithMiddlewareDecoder sets the Decoder used by Middleware, such as the one with WithLimits.
+// The default is a Decoder without options.
+func WithMiddlewareDecoder(dec *Decoder) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.decoder = dec
+	}
+}
+
+type contextKey[T interface{}] struct{}
+
+// Middleware returns the handler that decodes the URL query of the request into a new T,
+// and calls next with the value stored in the request context.
+//
+// The errors of all fields are reported at once.
+// If *T implements Validator, it is validated after decoding.
+// Use FromContext to retrieve the value in next.
+func Middleware[T interface{}](next http.Handler, opts ...MiddlewareOption) http.Handler {
+	c := middlewareConfig{
+		errorHandler: DefaultErrorHandler,
+		decoder:      NewDecoder(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := new(T)
+		d := decoder{config: c.decoder.config, query: r.URL.RawQuery, collectErrors: true}
+		err := d.decode(v)
+		if err == nil {
+			if vv, ok := interface{}(v).(Validator); ok {
+				err = vv.Validate()
+			}
+		}
+		if err != nil {
+			c.errorHandler(w, r, err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), contextKey[T]{}, v)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// FromContext returns the value stored by Middleware.
+func FromContext[T interface{}](ctx context.Context) (*T, bool) {
+	v, ok := ctx.Value(contextKey[T]{}).(*T)
+	return v, ok
+}
+
+// ErrorResponse is the response body of DefaultErrorHandler.
+type ErrorResponse struct {
+	Message string               `json:"message"`
+	Fields  []ErrorResponseField `json:"fields,omitempty"`
+}
+
+// ErrorResponseField is the error of the key in ErrorResponse.
+type ErrorResponseField struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// DefaultErrorHandler responds ErrorResponse as JSON with 400 Bad Request.
+//
+// If the error is caused by the type of the value rather than the request,
+// it responds 500 Internal Server Error.
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusBadRequest
+	res := ErrorResponse{Message: "invalid query parameters"}
+
+	var fes FieldErrors
+	var fe *FieldError
+	switch {
+	case isArgumentError(err):
+		status = http.StatusInternalServerError
+		res.Message = http.StatusText(status)
+	case errors.As(err, &fes):
+		for _, e := range fes {
+			res.Fields = append(res.Fields, ErrorResponseField{Key: e.Key, Message: e.Error()})
+		}
+	case errors.As(err, &fe):
+		res.Fields = append(res.Fields, ErrorResponseField{Key: fe.Key, Message: fe.Error()})
+	default:
+		res.Message = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,124 @@
+package qstring_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+type middlewareChild struct {
+	Price int `qstring:"price"`
+}
+
+type middlewareQuery struct {
+	ID    int             `qstring:"id"`
+	Name  string          `qstring:"name"`
+	Child middlewareChild `qstring:"child"`
+}
+
+func (q *middlewareQuery) Validate() error {
+	if q.Name == "invalid" {
+		return &qstring.FieldError{Key: "name", Err: errors.New("name is invalid")}
+	}
+	return nil
+}
+
+func TestMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, ok := qstring.FromContext[middlewareQuery](r.Context())
+		if !ok {
+			t.Fatalf("FromContext() should returns the value")
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(v.Name))
+	})
+
+	testCases := []struct {
+		name   string
+		target string
+		opts   []qstring.MiddlewareOption
+		status int
+		body   string
+	}{
+		{name: "success", target: "/?id=1&name=foo", status: http.StatusOK, body: "foo"},
+		{name: "invalid values", target: "/?id=a&name=foo&child[price]=b", status: http.StatusBadRequest,
+			body: `{"message":"invalid query parameters","fields":[{"key":"id","message":"\"a\" can not be assign to int"},{"key":"child[price]","message":"\"b\" can not be assign to int"}]}`},
+		{name: "validation error", target: "/?name=invalid", status: http.StatusBadRequest,
+			body: `{"message":"invalid query parameters","fields":[{"key":"name","message":"name is invalid"}]}`},
+		{name: "invalid query", target: "/?id=1;", status: http.StatusBadRequest,
+			body: `{"message":"invalid semicolon separator in query"}`},
+		{name: "limits of the decoder", target: "/?id=1&name=foo&child[price]=2", status: http.StatusBadRequest,
+			opts: []qstring.MiddlewareOption{qstring.WithMiddlewareDecoder(qstring.NewDecoder(qstring.WithLimits(qstring.Limits{MaxParams: 2})))},
+			body: `{"message":"query exceeds the limit of MaxParams (2)"}`},
+		{name: "custom error handler", target: "/?id=a", status: http.StatusTeapot, body: "id",
+			opts: []qstring.MiddlewareOption{qstring.WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+				var fes qstring.FieldErrors
+				if !errors.As(err, &fes) {
+					t.Fatalf("error should be qstring.FieldErrors, got %#v", err)
+				}
+				w.WriteHeader(http.StatusTeapot)
+				_, _ = w.Write([]byte(fes[0].Key))
+			})}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			qstring.Middleware[middlewareQuery](next, tc.opts...).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+			if w.Code != tc.status {
+				t.Errorf("status returns %d, want %d", w.Code, tc.status)
+			}
+			if body := strings.TrimSpace(w.Body.String()); body != tc.body {
+				t.Errorf("body returns \n%s\nwant \n%s", body, tc.body)
+			}
+		})
+	}
+
+	t.Run("unsupported type", func(t *testing.T) {
+		type s struct {
+			Field complex64 `qstring:"field"`
+		}
+		w := httptest.NewRecorder()
+		qstring.Middleware[s](next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?field=1", nil))
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("status returns %d, want %d", w.Code, http.StatusInternalServerError)
+		}
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if v, ok := qstring.FromContext[middlewareQuery](r.Context()); ok || v != nil {
+		t.Errorf("FromContext() returns %v, %v, want nil, false", v, ok)
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	errSentinel := errors.New("sentinel")
+	fes := qstring.FieldErrors{
+		{Key: "a", Err: fmt.Errorf("a: %w", errSentinel)},
+		{Key: "b", Err: &qstring.DuplicateKeyError{Key: "b", Values: []string{"1", "2"}}},
+	}
+
+	// call the methods directly, errors.As and errors.Is before Go 1.20 do not follow Unwrap() []error
+	var de *qstring.DuplicateKeyError
+	if !fes.As(&de) || de.Key != "b" {
+		t.Errorf("As() should find DuplicateKeyError, got %#v", de)
+	}
+	var le *qstring.LimitExceededError
+	if fes.As(&le) {
+		t.Errorf("As() should not find LimitExceededError")
+	}
+	if !fes.Is(errSentinel) {
+		t.Errorf("Is() should find the sentinel error")
+	}
+	if fes.Is(errors.New("other")) {
+		t.Errorf("Is() should not find the other error")
+	}
+}
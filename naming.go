@@ -0,0 +1,121 @@
+package qstring
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// skipTag is the tag to exclude the field
+const skipTag = "-"
+
+// NamingStrategy converts the name of the struct field to the key of the query string.
+type NamingStrategy func(fieldName string) string
+
+// WithNaming sets the naming strategy for the exported fields without the "qstring" tag.
+// By default, such fields are skipped.
+//
+// The fields with the tag `qstring:"-"` are always skipped,
+// and the tag with only options (e.g. `qstring:",omitempty"`) uses the strategy for the key.
+// Use SnakeCase, CamelCase, KebabCase, LowerCase or a custom func.
+func WithNaming(strategy NamingStrategy) Option {
+	return func(c *config) {
+		c.naming = strategy
+	}
+}
+
+// SnakeCase converts "UserID" to "user_id".
+func SnakeCase(fieldName string) string {
+	return strings.Join(lowerWords(fieldName), "_")
+}
+
+// KebabCase converts "UserID" to "user-id".
+func KebabCase(fieldName string) string {
+	return strings.Join(lowerWords(fieldName), "-")
+}
+
+// CamelCase converts "UserID" to "userId".
+func CamelCase(fieldName string) string {
+	words := lowerWords(fieldName)
+	for i := 1; i < len(words); i++ {
+		r, size := utf8.DecodeRuneInString(words[i])
+		words[i] = string(unicode.ToUpper(r)) + words[i][size:]
+	}
+	return strings.Join(words, "")
+}
+
+// LowerCase converts "UserID" to "userid".
+func LowerCase(fieldName string) string {
+	return strings.ToLower(fieldName)
+}
+
+// fieldTag returns the key and the options of the struct field.
+// The key is empty if the field is skipped.
+// The embedded struct without the key is inlined with WithInlineEmbedded.
+func (c *config) fieldTag(f reflect.StructField) (string, tagOption) {
+	s, name := c.lookupTag(f.Tag)
+	if s == skipTag {
+		return "", tagOption{}
+	}
+
+	tag, opt := parseTag(s)
+	// the options of the other tags (e.g. "string" of "json") are not errors
+	if name != tagName {
+		opt.invalid = ""
+	}
+	if tag == "" && c.isEmbeddedStruct(f) {
+		opt.inline = true
+		return "", opt
+	}
+
+	if !f.IsExported() {
+		return "", tagOption{}
+	}
+	if tag == "" && c.naming != nil {
+		tag = c.naming(f.Name)
+	}
+	return tag, opt
+}
+
+// isEmbeddedStruct reports whether the field is the embedded struct to be inlined.
+// The exported fields of the unexported struct type are also promoted,
+// but the unexported pointer cannot be allocated.
+func (c *config) isEmbeddedStruct(f reflect.StructField) bool {
+	if !c.inlineEmbedded || !f.Anonymous {
+		return false
+	}
+
+	rt := f.Type
+	if rt.Kind() == reflect.Ptr {
+		if !f.IsExported() {
+			return false
+		}
+		rt = rt.Elem()
+	}
+	if _, ok := c.typeDecoder(rt); ok {
+		return false
+	}
+	return rt.Kind() == reflect.Struct && rt != orderedQType
+}
+
+// lowerWords splits "HTTPServerID2" to `[]string{"http", "server", "id2"}`
+func lowerWords(s string) []string {
+	var words []string
+	rs := []rune(s)
+	start := 0
+	for i := 1; i < len(rs); i++ {
+		if !unicode.IsUpper(rs[i]) {
+			continue
+		}
+		// "aB" or the end of the acronym "ABc"
+		if !unicode.IsUpper(rs[i-1]) || (i+1 < len(rs) && unicode.IsLower(rs[i+1])) {
+			words = append(words, strings.ToLower(string(rs[start:i])))
+			start = i
+		}
+	}
+	if start < len(rs) {
+		words = append(words, strings.ToLower(string(rs[start:])))
+	}
+	return words
+}
@@ -0,0 +1,89 @@
+package qstring_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestNamingStrategy(t *testing.T) {
+	testCases := []struct {
+		name  string
+		field string
+		snake string
+		camel string
+		kebab string
+		lower string
+	}{
+		{name: "single word", field: "Name", snake: "name", camel: "name", kebab: "name", lower: "name"},
+		{name: "two words", field: "PageSize", snake: "page_size", camel: "pageSize", kebab: "page-size", lower: "pagesize"},
+		{name: "acronym at the end", field: "UserID", snake: "user_id", camel: "userId", kebab: "user-id", lower: "userid"},
+		{name: "acronym at the start", field: "HTTPServer", snake: "http_server", camel: "httpServer", kebab: "http-server", lower: "httpserver"},
+		{name: "only acronym", field: "ID", snake: "id", camel: "id", kebab: "id", lower: "id"},
+		{name: "non-ASCII", field: "FooÉtat", snake: "foo_état", camel: "fooÉtat", kebab: "foo-état", lower: "fooétat"},
+		{name: "digits", field: "Address2Line", snake: "address2_line", camel: "address2Line", kebab: "address2-line", lower: "address2line"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := []string{
+				qstring.SnakeCase(tc.field),
+				qstring.CamelCase(tc.field),
+				qstring.KebabCase(tc.field),
+				qstring.LowerCase(tc.field),
+			}
+			expected := []string{tc.snake, tc.camel, tc.kebab, tc.lower}
+			assertResult(t, "NamingStrategy", actual, nil, expected, nil)
+		})
+	}
+}
+
+func TestWithNaming(t *testing.T) {
+	type s struct {
+		PageSize int
+		UserID   string
+		Tagged   string `qstring:"t"`
+		Skipped  string `qstring:"-"`
+		Empty    string `qstring:",omitempty"`
+		private  string
+	}
+	v := s{PageSize: 10, UserID: "u1", Tagged: "x", Skipped: "no", private: "no"}
+
+	testCases := []struct {
+		name     string
+		strategy qstring.NamingStrategy
+		expected string
+	}{
+		{name: "default", strategy: nil, expected: "t=x"},
+		{name: "snake case", strategy: qstring.SnakeCase, expected: "page_size=10&t=x&user_id=u1"},
+		{name: "camel case", strategy: qstring.CamelCase, expected: "pageSize=10&t=x&userId=u1"},
+		{name: "kebab case", strategy: qstring.KebabCase, expected: "page-size=10&t=x&user-id=u1"},
+		{name: "lower case", strategy: qstring.LowerCase, expected: "pagesize=10&t=x&userid=u1"},
+		{name: "custom", strategy: strings.ToUpper, expected: "PAGESIZE=10&USERID=u1&t=x"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := qstring.NewEncoder(qstring.WithNaming(tc.strategy)).Encode(v)
+			assertResult(t, "Encode()", actual, err, tc.expected, nil)
+
+			var decoded s
+			err = qstring.NewDecoder(qstring.WithNaming(tc.strategy)).Decode(tc.expected+"&-=no&Skipped=no&skipped=no&private=no", &decoded)
+			expected := s{Tagged: "x"}
+			if tc.strategy != nil {
+				expected.PageSize, expected.UserID = 10, "u1"
+			}
+			assertResult(t, "Decode()", decoded, err, expected, nil)
+		})
+	}
+
+	t.Run("tag with only options", func(t *testing.T) {
+		enc := qstring.NewEncoder(qstring.WithNaming(qstring.SnakeCase))
+		actual, err := enc.Encode(struct {
+			UserID   string `qstring:",omitempty"`
+			PageSize int    `qstring:",omitempty"`
+		}{PageSize: 10})
+		assertResult(t, "Encode()", actual, err, "page_size=10", nil)
+	})
+}
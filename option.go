@@ -0,0 +1,50 @@
+package qstring
+
+import "reflect"
+
+// Option is an option for NewEncoder and NewDecoder.
+//
+// An option that only affects encoding is ignored by Decoder, and vice versa.
+type Option func(*config)
+
+type config struct {
+	// sealKeys is the keys for the "sealed" fields, the first key is used for encryption
+	sealKeys []SealKey
+	// escaping is the profile of percent-encoding
+	escaping Escaping
+	// boolTrue and boolFalse are the accepted representations of bool, nil means the default
+	boolTrue  []string
+	boolFalse []string
+	// boolCaseInsensitive compares the representations of bool case-insensitively
+	boolCaseInsensitive bool
+	// presenceAsTrue decodes the key without value (e.g. "?verbose") as true
+	presenceAsTrue bool
+	// disallowUnclaimed reports the keys claimed by no target of DecodeInto
+	disallowUnclaimed bool
+	// naming is the naming strategy for the fields without the tag, nil skips them
+	naming NamingStrategy
+	// tagNames is the keys of the struct tag to look up in order, nil means "qstring"
+	tagNames []string
+	// inlineEmbedded inlines the embedded structs without the key
+	inlineEmbedded bool
+	// aliasHandler is called when a field is decoded from its alias
+	aliasHandler func(name, alias string)
+	// caseInsensitiveKeys and keyNormalizer change the matching of the keys to the struct fields
+	caseInsensitiveKeys bool
+	keyNormalizer       func(key string) string
+	// limits is the limits of the query string accepted by Decoder
+	limits Limits
+	// duplicatePolicy and joinSeparator are the rule for the duplicate keys of single value fields
+	duplicatePolicy DuplicatePolicy
+	joinSeparator   string
+	// types is the conversion functions of the registered types
+	types map[reflect.Type]typeConverter
+}
+
+func newConfig(opts []Option) config {
+	c := config{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
@@ -0,0 +1,90 @@
+package qstring
+
+import "reflect"
+
+var orderedQType = reflect.TypeOf(OrderedQ{})
+
+// OrderedQ is the type of the query string parameters
+// that preserves the order of the keys.
+//
+// Nested maps are stored as *OrderedQ and nested lists as S.
+// The zero value is an empty map ready to use.
+type OrderedQ struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewOrderedQ returns an empty OrderedQ.
+func NewOrderedQ() *OrderedQ {
+	return &OrderedQ{}
+}
+
+// Get returns the value stored under the key.
+// The second return value reports whether the key was present.
+func (q *OrderedQ) Get(key string) (interface{}, bool) {
+	if q == nil || q.values == nil {
+		return nil, false
+	}
+	v, ok := q.values[key]
+	return v, ok
+}
+
+// Set stores the value under the key.
+// A new key is appended at the end, an existing key keeps its position.
+func (q *OrderedQ) Set(key string, value interface{}) {
+	if q.values == nil {
+		q.values = make(map[string]interface{})
+	}
+	if _, ok := q.values[key]; !ok {
+		q.keys = append(q.keys, key)
+	}
+	q.values[key] = value
+}
+
+// Delete removes the key.
+func (q *OrderedQ) Delete(key string) {
+	if q == nil || q.values == nil {
+		return
+	}
+	if _, ok := q.values[key]; !ok {
+		return
+	}
+	delete(q.values, key)
+	for i, k := range q.keys {
+		if k == key {
+			q.keys = append(q.keys[:i:i], q.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Keys returns the keys in order.
+func (q *OrderedQ) Keys() []string {
+	if q == nil {
+		return nil
+	}
+	keys := make([]string, len(q.keys))
+	copy(keys, q.keys)
+	return keys
+}
+
+// Len returns the number of the keys.
+func (q *OrderedQ) Len() int {
+	if q == nil {
+		return 0
+	}
+	return len(q.keys)
+}
+
+// Range calls f for each key and value in order.
+// If f returns false, Range stops the iteration.
+func (q *OrderedQ) Range(f func(key string, value interface{}) bool) {
+	if q == nil {
+		return
+	}
+	for _, k := range q.keys {
+		if !f(k, q.values[k]) {
+			return
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package qstring_test
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestOrderedQ(t *testing.T) {
+	t.Run("set and get", func(t *testing.T) {
+		q := qstring.NewOrderedQ()
+		q.Set("b", "1")
+		q.Set("a", "2")
+		q.Set("b", "3")
+
+		if v, ok := q.Get("b"); !ok || v != "3" {
+			t.Errorf("Get() returns %v, %v, want %v, %v", v, ok, "3", true)
+		}
+		if v, ok := q.Get("c"); ok || v != nil {
+			t.Errorf("Get() returns %v, %v, want %v, %v", v, ok, nil, false)
+		}
+		if keys := q.Keys(); !reflect.DeepEqual(keys, []string{"b", "a"}) {
+			t.Errorf("Keys() returns %v, want %v", keys, []string{"b", "a"})
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		q := qstring.NewOrderedQ()
+		q.Set("a", "1")
+		q.Set("b", "2")
+		q.Set("c", "3")
+		q.Delete("b")
+		q.Delete("no")
+
+		if keys := q.Keys(); !reflect.DeepEqual(keys, []string{"a", "c"}) {
+			t.Errorf("Keys() returns %v, want %v", keys, []string{"a", "c"})
+		}
+		if q.Len() != 2 {
+			t.Errorf("Len() returns %d, want %d", q.Len(), 2)
+		}
+	})
+
+	t.Run("range", func(t *testing.T) {
+		q := qstring.NewOrderedQ()
+		q.Set("c", "1")
+		q.Set("a", "2")
+		q.Set("b", "3")
+
+		var keys []string
+		q.Range(func(key string, value interface{}) bool {
+			keys = append(keys, key)
+			return key != "a"
+		})
+		if !reflect.DeepEqual(keys, []string{"c", "a"}) {
+			t.Errorf("Range() iterates %v, want %v", keys, []string{"c", "a"})
+		}
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var q qstring.OrderedQ
+		if _, ok := q.Get("a"); ok {
+			t.Errorf("Get() should returns false")
+		}
+		q.Delete("a")
+		q.Set("a", "1")
+		if q.Len() != 1 {
+			t.Errorf("Len() returns %d, want %d", q.Len(), 1)
+		}
+	})
+}
+
+func TestOrderedQ_decode(t *testing.T) {
+	q, err := qstring.DecodeToOrderedMap("z=1&a[y]=2&a[b]=3&m[1]=x&m[0]=y&a[c][]=4&d=5&d=6")
+	if err != nil {
+		t.Fatalf("DecodeToOrderedMap() should not returns error, got %q", err)
+	}
+
+	if keys := q.Keys(); !reflect.DeepEqual(keys, []string{"z", "a", "m", "d"}) {
+		t.Errorf("Keys() returns %v, want %v", keys, []string{"z", "a", "m", "d"})
+	}
+
+	a, _ := q.Get("a")
+	aq, ok := a.(*qstring.OrderedQ)
+	if !ok {
+		t.Fatalf("Get() returns %T, want *qstring.OrderedQ", a)
+	}
+	if keys := aq.Keys(); !reflect.DeepEqual(keys, []string{"y", "b", "c"}) {
+		t.Errorf("Keys() returns %v, want %v", keys, []string{"y", "b", "c"})
+	}
+	if c, _ := aq.Get("c"); !reflect.DeepEqual(c, []string{"4"}) {
+		t.Errorf("Get() returns %#v, want %#v", c, []string{"4"})
+	}
+	if m, _ := q.Get("m"); !reflect.DeepEqual(m, []string{"y", "x"}) {
+		t.Errorf("Get() returns %#v, want %#v", m, []string{"y", "x"})
+	}
+	if d, _ := q.Get("d"); !reflect.DeepEqual(d, []string{"5", "6"}) {
+		t.Errorf("Get() returns %#v, want %#v", d, []string{"5", "6"})
+	}
+}
+
+func TestOrderedQ_nestedInSlice(t *testing.T) {
+	q, err := qstring.DecodeToOrderedMap("items[0][z]=1&items[0][a]=2")
+	if err != nil {
+		t.Fatalf("DecodeToOrderedMap() should not returns error, got %q", err)
+	}
+
+	items, _ := q.Get("items")
+	s, ok := items.(qstring.S)
+	if !ok || len(s) != 1 {
+		t.Fatalf("Get() returns %#v, want qstring.S with 1 element", items)
+	}
+	item, ok := s[0].(*qstring.OrderedQ)
+	if !ok {
+		t.Fatalf("S[0] is %T, want *qstring.OrderedQ", s[0])
+	}
+	if keys := item.Keys(); !reflect.DeepEqual(keys, []string{"z", "a"}) {
+		t.Errorf("Keys() returns %v, want %v", keys, []string{"z", "a"})
+	}
+}
+
+func TestOrderedQ_manyIndexes(t *testing.T) {
+	params := make([]string, 0, 12)
+	for i := 0; i < 12; i++ {
+		params = append(params, fmt.Sprintf("l[%d][n]=%d", i, i))
+	}
+	query := strings.Join(params, "&")
+
+	q, err := qstring.DecodeToOrderedMap(query)
+	if err != nil {
+		t.Fatalf("DecodeToOrderedMap() should not returns error, got %q", err)
+	}
+	l, _ := q.Get("l")
+	s, ok := l.(qstring.S)
+	if !ok || len(s) != 12 {
+		t.Fatalf("Get() returns %#v, want qstring.S with 12 elements", l)
+	}
+	for i, v := range s {
+		if n, _ := v.(*qstring.OrderedQ).Get("n"); n != strconv.Itoa(i) {
+			t.Errorf("S[%d] has n=%v, want %d", i, n, i)
+		}
+	}
+
+	m, err := qstring.DecodeToMap(query)
+	if err != nil {
+		t.Fatalf("DecodeToMap() should not returns error, got %q", err)
+	}
+	if n, err := m.Get("l.2.n"); err != nil || n != "2" {
+		t.Errorf("Get() returns %v, %v, want %q", n, err, "2")
+	}
+}
+
+func TestOrderedQ_roundTrip(t *testing.T) {
+	testCases := []struct {
+		name string
+		q    string
+	}{
+		{name: "flat", q: "z=1&a=2&m=3"},
+		{name: "nested map", q: "z=1&f[y]=2&f[b]=3&a=4"},
+		{name: "nested list", q: "z=1&l[0]=a&l[1]=b&a=2"},
+		{name: "nested list of map", q: "z=1&l[0][y]=a&l[0][b]=b&a=2"},
+		{name: "nested map of map", q: "z=1&f[y][d]=1&f[y][c]=2&f[b]=3&a=4"},
+		{name: "empty value", q: "z=&a=1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := qstring.DecodeToOrderedMap(tc.q)
+			if err != nil {
+				t.Fatalf("DecodeToOrderedMap() should not returns error, got %q", err)
+			}
+			actual, err := qstring.Encode(q)
+			if err != nil {
+				t.Fatalf("Encode() should not returns error, got %q", err)
+			}
+			actual = unescapeBrackets(actual)
+			if actual != tc.q {
+				t.Errorf("Encode() returns %q, want %q", actual, tc.q)
+			}
+		})
+	}
+}
+
+func TestOrderedQ_structField(t *testing.T) {
+	type s struct {
+		Field  qstring.OrderedQ  `qstring:"field"`
+		FieldP *qstring.OrderedQ `qstring:"field_p"`
+	}
+
+	v := s{}
+	if err := qstring.Decode("field[b]=1&field[a]=2&field_p[d]=3&field_p[c]=4", &v); err != nil {
+		t.Fatalf("Decode() should not returns error, got %q", err)
+	}
+	if keys := v.Field.Keys(); !reflect.DeepEqual(keys, []string{"b", "a"}) {
+		t.Errorf("Keys() returns %v, want %v", keys, []string{"b", "a"})
+	}
+	if keys := v.FieldP.Keys(); !reflect.DeepEqual(keys, []string{"d", "c"}) {
+		t.Errorf("Keys() returns %v, want %v", keys, []string{"d", "c"})
+	}
+
+	actual, err := qstring.Encode(v)
+	if err != nil {
+		t.Fatalf("Encode() should not returns error, got %q", err)
+	}
+	expected := "field[b]=1&field[a]=2&field_p[d]=3&field_p[c]=4"
+	if a := unescapeBrackets(actual); a != expected {
+		t.Errorf("Encode() returns %q, want %q", a, expected)
+	}
+
+	if err := qstring.Decode("field[b][y]=1&field[b][x]=2&field[a]=3", &v); err != nil {
+		t.Fatalf("Decode() should not returns error, got %q", err)
+	}
+	actual, err = qstring.Encode(v)
+	if err != nil {
+		t.Fatalf("Encode() should not returns error, got %q", err)
+	}
+	expected = "field[b][y]=1&field[b][x]=2&field[a]=3&field_p[d]=3&field_p[c]=4"
+	if a := unescapeBrackets(actual); a != expected {
+		t.Errorf("Encode() returns %q, want %q", a, expected)
+	}
+
+	if err := qstring.Decode("field=1", &v); err == nil {
+		t.Errorf("Decode() should returns error")
+	}
+}
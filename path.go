@@ -0,0 +1,336 @@
+package qstring
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	expectedContainer = "map or slice"
+	expectedMap       = "map"
+	expectedString    = "string"
+)
+
+// Has reports whether the value exists on the key path.
+//
+// The key path is specified in dot form (e.g. "filter.items.0.price")
+// or bracket form (e.g. "filter[items][0][price]").
+func (q Q) Has(path string) bool {
+	_, err := q.Get(path)
+	return err == nil
+}
+
+// Get returns the value on the key path.
+//
+// The key path is specified in dot form (e.g. "filter.items.0.price")
+// or bracket form (e.g. "filter[items][0][price]").
+// The index of S and []string is specified as a number.
+func (q Q) Get(path string) (interface{}, error) {
+	keys, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cur interface{} = q
+	for i, k := range keys {
+		switch v := normalizePathValue(cur).(type) {
+		case Q:
+			val, ok := v[k]
+			if !ok {
+				return nil, &pathNotFoundError{joinPath(keys[:i+1])}
+			}
+			cur = val
+		case S:
+			idx, ok := pathIndex(k, len(v))
+			if !ok {
+				return nil, &pathNotFoundError{joinPath(keys[:i+1])}
+			}
+			cur = v[idx]
+		case []string:
+			idx, ok := pathIndex(k, len(v))
+			if !ok {
+				return nil, &pathNotFoundError{joinPath(keys[:i+1])}
+			}
+			cur = v[idx]
+		default:
+			return nil, &pathTypeError{joinPath(keys[:i]), reflect.TypeOf(cur), expectedContainer}
+		}
+	}
+	return cur, nil
+}
+
+// GetString returns the string value on the key path.
+func (q Q) GetString(path string) (string, error) {
+	v, err := q.Get(path)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", &pathTypeError{path, reflect.TypeOf(v), expectedString}
+	}
+	return s, nil
+}
+
+// GetInt returns the value on the key path as int.
+func (q Q) GetInt(path string) (int, error) {
+	s, err := q.GetString(path)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, &noAssignableValueError{reflect.TypeOf(i), s}
+	}
+	return i, nil
+}
+
+// GetStrings returns the value on the key path as []string.
+//
+// A string value is returned as a slice with one element.
+// S is accepted if all elements are string.
+func (q Q) GetStrings(path string) ([]string, error) {
+	v, err := q.Get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch vv := normalizePathValue(v).(type) {
+	case string:
+		return []string{vv}, nil
+	case []string:
+		return vv, nil
+	case S:
+		ss := make([]string, 0, len(vv))
+		for _, e := range vv {
+			s, ok := e.(string)
+			if !ok {
+				return nil, &pathTypeError{path, reflect.TypeOf(v), "[]string"}
+			}
+			ss = append(ss, s)
+		}
+		return ss, nil
+	}
+	return nil, &pathTypeError{path, reflect.TypeOf(v), "[]string"}
+}
+
+// GetQ returns the Q value on the key path.
+func (q Q) GetQ(path string) (Q, error) {
+	v, err := q.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := normalizePathValue(v).(Q)
+	if !ok {
+		return nil, &pathTypeError{path, reflect.TypeOf(v), "qstring.Q"}
+	}
+	return m, nil
+}
+
+// Set sets the value on the key path.
+//
+// The missing intermediate values are created as Q,
+// or as S if the next key is a number.
+// The index of S and []string can be specified up to its length,
+// the length appends the value to the end.
+func (q Q) Set(path string, value interface{}) error {
+	keys, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if q == nil {
+		return &pathTypeError{"", reflect.TypeOf(q), "non-nil qstring.Q"}
+	}
+	_, err = setPath(q, keys, 0, value)
+	return err
+}
+
+// Delete deletes the value on the key path.
+//
+// The elements of S and []string after the deleted element are shifted.
+// It does nothing if the key path does not exist.
+func (q Q) Delete(path string) error {
+	keys, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	_, err = deletePath(q, keys, 0)
+	return err
+}
+
+func setPath(node interface{}, keys []string, i int, value interface{}) (interface{}, error) {
+	if i == len(keys) {
+		return value, nil
+	}
+
+	k := keys[i]
+	if node == nil {
+		if _, err := strconv.Atoi(k); err == nil {
+			node = S{}
+		} else {
+			node = Q{}
+		}
+	}
+
+	switch v := normalizePathValue(node).(type) {
+	case Q:
+		child, err := setPath(v[k], keys, i+1, value)
+		if err != nil {
+			return nil, err
+		}
+		v[k] = child
+		return v, nil
+	case S:
+		if _, err := strconv.Atoi(k); err != nil {
+			return nil, &pathTypeError{joinPath(keys[:i]), reflect.TypeOf(node), expectedMap}
+		}
+		idx, ok := pathIndex(k, len(v)+1)
+		if !ok {
+			return nil, &pathNotFoundError{joinPath(keys[:i+1])}
+		}
+		if idx == len(v) {
+			v = append(v, nil)
+		}
+		child, err := setPath(v[idx], keys, i+1, value)
+		if err != nil {
+			return nil, err
+		}
+		v[idx] = child
+		return v, nil
+	case []string:
+		if _, err := strconv.Atoi(k); err != nil {
+			return nil, &pathTypeError{joinPath(keys[:i]), reflect.TypeOf(node), expectedMap}
+		}
+		idx, ok := pathIndex(k, len(v)+1)
+		if !ok {
+			return nil, &pathNotFoundError{joinPath(keys[:i+1])}
+		}
+		if i != len(keys)-1 {
+			return nil, &pathTypeError{joinPath(keys[:i+1]), reflect.TypeOf(""), expectedContainer}
+		}
+		s, ok := value.(string)
+		if !ok {
+			return nil, &pathTypeError{joinPath(keys), reflect.TypeOf(value), expectedString}
+		}
+		if idx == len(v) {
+			return append(v, s), nil
+		}
+		v[idx] = s
+		return v, nil
+	}
+
+	return nil, &pathTypeError{joinPath(keys[:i]), reflect.TypeOf(node), expectedContainer}
+}
+
+func deletePath(node interface{}, keys []string, i int) (interface{}, error) {
+	k := keys[i]
+	last := i == len(keys)-1
+
+	switch v := normalizePathValue(node).(type) {
+	case Q:
+		child, ok := v[k]
+		if !ok {
+			return v, nil
+		}
+		if last {
+			delete(v, k)
+			return v, nil
+		}
+		child, err := deletePath(child, keys, i+1)
+		if err != nil {
+			return nil, err
+		}
+		v[k] = child
+		return v, nil
+	case S:
+		idx, ok := pathIndex(k, len(v))
+		if !ok {
+			return v, nil
+		}
+		if last {
+			return append(v[:idx:idx], v[idx+1:]...), nil
+		}
+		child, err := deletePath(v[idx], keys, i+1)
+		if err != nil {
+			return nil, err
+		}
+		v[idx] = child
+		return v, nil
+	case []string:
+		idx, ok := pathIndex(k, len(v))
+		if !ok {
+			return v, nil
+		}
+		if !last {
+			return nil, &pathTypeError{joinPath(keys[:i+1]), reflect.TypeOf(""), expectedContainer}
+		}
+		return append(v[:idx:idx], v[idx+1:]...), nil
+	}
+
+	return nil, &pathTypeError{joinPath(keys[:i]), reflect.TypeOf(node), expectedContainer}
+}
+
+// normalizePathValue converts the unnamed types to Q and S
+func normalizePathValue(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		return Q(vv)
+	case []interface{}:
+		return S(vv)
+	}
+	return v
+}
+
+// pathIndex converts the key to the index less than n
+func pathIndex(key string, n int) (int, bool) {
+	idx, err := strconv.Atoi(key)
+	if err != nil || idx < 0 || idx >= n {
+		return 0, false
+	}
+	return idx, true
+}
+
+// parsePath converts `a.b[c].0` and `a[b][c][0]` to `[]string{"a", "b", "c", "0"}`
+func parsePath(path string) ([]string, error) {
+	var keys []string
+
+	for i := 0; i < len(path); {
+		var key string
+		if path[i] == '[' {
+			end := strings.IndexByte(path[i:], ']')
+			if end <= 1 {
+				return nil, &invalidPathError{path}
+			}
+			key = path[i+1 : i+end]
+			i += end + 1
+		} else {
+			end := strings.IndexAny(path[i:], ".[")
+			if end == -1 {
+				end = len(path) - i
+			}
+			if end == 0 {
+				return nil, &invalidPathError{path}
+			}
+			key = path[i : i+end]
+			i += end
+		}
+		keys = append(keys, key)
+
+		if i < len(path) && path[i] == '.' {
+			i++
+			if i == len(path) {
+				return nil, &invalidPathError{path}
+			}
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, &invalidPathError{path}
+	}
+	return keys, nil
+}
+
+func joinPath(keys []string) string {
+	return strings.Join(keys, ".")
+}
@@ -0,0 +1,183 @@
+package qstring_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func newPathQ() qstring.Q {
+	return qstring.Q{
+		"page": "2",
+		"tags": []string{"a", "b"},
+		"filter": qstring.Q{
+			"name": "x",
+			"items": qstring.S{
+				qstring.Q{"price": "100"},
+				qstring.Q{"price": "abc"},
+			},
+		},
+	}
+}
+
+func TestQ_Get(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		err      error
+		expected interface{}
+	}{
+		{name: "top level", path: "page", expected: "2"},
+		{name: "dot form", path: "filter.items.0.price", expected: "100"},
+		{name: "bracket form", path: "filter[items][1][price]", expected: "abc"},
+		{name: "mixed form", path: "filter.items[1].price", expected: "abc"},
+		{name: "string slice index", path: "tags.1", expected: "b"},
+		{name: "map", path: "filter.items.0", expected: qstring.Q{"price": "100"}},
+		{name: "not found key", path: "filter.no", err: fmt.Errorf(`"filter.no" is not found`)},
+		{name: "out of range", path: "filter.items.2", err: fmt.Errorf(`"filter.items.2" is not found`)},
+		{name: "not index", path: "tags.a", err: fmt.Errorf(`"tags.a" is not found`)},
+		{name: "cross string", path: "page.a", err: fmt.Errorf(`"page" is string, not map or slice`)},
+		{name: "cross string slice element", path: "tags.0.a", err: fmt.Errorf(`"tags.0" is string, not map or slice`)},
+		{name: "empty path", path: "", err: fmt.Errorf(`invalid path ""`)},
+		{name: "empty key", path: "filter..name", err: fmt.Errorf(`invalid path "filter..name"`)},
+		{name: "empty bracket", path: "filter[]", err: fmt.Errorf(`invalid path "filter[]"`)},
+		{name: "trailing dot", path: "filter.", err: fmt.Errorf(`invalid path "filter."`)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := newPathQ().Get(tc.path)
+			assertResult(t, "Get()", actual, err, tc.expected, tc.err)
+		})
+	}
+}
+
+func TestQ_typedGetter(t *testing.T) {
+	q := newPathQ()
+
+	t.Run("GetString", func(t *testing.T) {
+		actual, err := q.GetString("filter.name")
+		assertResult(t, "GetString()", actual, err, "x", nil)
+		_, err = q.GetString("tags")
+		assertResult(t, "GetString()", nil, err, nil, fmt.Errorf(`"tags" is []string, not string`))
+	})
+
+	t.Run("GetInt", func(t *testing.T) {
+		actual, err := q.GetInt("filter.items.0.price")
+		assertResult(t, "GetInt()", actual, err, 100, nil)
+		_, err = q.GetInt("filter.items.1.price")
+		assertResult(t, "GetInt()", nil, err, nil, fmt.Errorf(`"abc" can not be assign to int`))
+	})
+
+	t.Run("GetStrings", func(t *testing.T) {
+		actual, err := q.GetStrings("tags")
+		assertResult(t, "GetStrings()", actual, err, []string{"a", "b"}, nil)
+		actual, err = q.GetStrings("page")
+		assertResult(t, "GetStrings()", actual, err, []string{"2"}, nil)
+		actual, err = qstring.Q{"s": qstring.S{"a", "b"}}.GetStrings("s")
+		assertResult(t, "GetStrings()", actual, err, []string{"a", "b"}, nil)
+		_, err = q.GetStrings("filter.items")
+		assertResult(t, "GetStrings()", nil, err, nil, fmt.Errorf(`"filter.items" is qstring.S, not []string`))
+	})
+
+	t.Run("GetQ", func(t *testing.T) {
+		actual, err := q.GetQ("filter.items.0")
+		assertResult(t, "GetQ()", actual, err, qstring.Q{"price": "100"}, nil)
+		actual, err = qstring.Q{"m": map[string]interface{}{"a": "1"}}.GetQ("m")
+		assertResult(t, "GetQ()", actual, err, qstring.Q{"a": "1"}, nil)
+		_, err = q.GetQ("page")
+		assertResult(t, "GetQ()", nil, err, nil, fmt.Errorf(`"page" is string, not qstring.Q`))
+	})
+
+	t.Run("Has", func(t *testing.T) {
+		if !q.Has("filter.items.1.price") {
+			t.Errorf("Has() should returns true")
+		}
+		if q.Has("filter.items.2.price") {
+			t.Errorf("Has() should returns false")
+		}
+	})
+}
+
+func TestQ_Set(t *testing.T) {
+	base := func() qstring.Q {
+		return qstring.Q{"page": "1", "tags": []string{"a", "b"}, "list": qstring.S{"a"}}
+	}
+	expected := func(k string, v interface{}) qstring.Q {
+		q := base()
+		q[k] = v
+		return q
+	}
+
+	testCases := []struct {
+		name     string
+		path     string
+		value    interface{}
+		err      error
+		expected qstring.Q
+	}{
+		{name: "replace", path: "page", value: "3", expected: expected("page", "3")},
+		{name: "create map", path: "filter.name", value: "x", expected: expected("filter", qstring.Q{"name": "x"})},
+		{name: "create slice", path: "filter[items][0][price]", value: "1", expected: expected("filter", qstring.Q{"items": qstring.S{qstring.Q{"price": "1"}}})},
+		{name: "append slice", path: "list.1", value: qstring.Q{"a": "1"}, expected: expected("list", qstring.S{"a", qstring.Q{"a": "1"}})},
+		{name: "append string slice", path: "tags.2", value: "c", expected: expected("tags", []string{"a", "b", "c"})},
+		{name: "replace string slice", path: "tags.0", value: "z", expected: expected("tags", []string{"z", "b"})},
+		{name: "out of range", path: "tags.3", value: "c", err: fmt.Errorf(`"tags.3" is not found`)},
+		{name: "not string to string slice", path: "tags.0", value: 1, err: fmt.Errorf(`"tags.0" is int, not string`)},
+		{name: "cross string", path: "page.a", value: "1", err: fmt.Errorf(`"page" is string, not map or slice`)},
+		{name: "cross string slice", path: "tags.0.a", value: "1", err: fmt.Errorf(`"tags.0" is string, not map or slice`)},
+		{name: "map key to slice", path: "list.a", value: "1", err: fmt.Errorf(`"list" is qstring.S, not map`)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := base()
+			err := q.Set(tc.path, tc.value)
+			assertResult(t, "Set()", q, err, tc.expected, tc.err)
+		})
+	}
+}
+
+func TestQ_Delete(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		err      error
+		expected qstring.Q
+	}{
+		{name: "map key", path: "filter.name", expected: qstring.Q{"tags": []string{"a", "b"}, "filter": qstring.Q{"items": qstring.S{"x", "y"}}}},
+		{name: "slice element", path: "filter.items.0", expected: qstring.Q{"tags": []string{"a", "b"}, "filter": qstring.Q{"name": "n", "items": qstring.S{"y"}}}},
+		{name: "string slice element", path: "tags[1]", expected: qstring.Q{"tags": []string{"a"}, "filter": qstring.Q{"name": "n", "items": qstring.S{"x", "y"}}}},
+		{name: "not found", path: "filter.no.a", expected: qstring.Q{"tags": []string{"a", "b"}, "filter": qstring.Q{"name": "n", "items": qstring.S{"x", "y"}}}},
+		{name: "cross string", path: "filter.name.a", err: fmt.Errorf(`"filter.name" is string, not map or slice`)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := qstring.Q{"tags": []string{"a", "b"}, "filter": qstring.Q{"name": "n", "items": qstring.S{"x", "y"}}}
+			err := q.Delete(tc.path)
+			assertResult(t, "Delete()", q, err, tc.expected, tc.err)
+		})
+	}
+}
+
+func assertResult(t *testing.T, fn string, actual interface{}, err error, expected interface{}, expectedErr error) {
+	t.Helper()
+	if err != nil {
+		if expectedErr == nil {
+			t.Fatalf("%s should not returns error, got %q", fn, err)
+		}
+		if err.Error() != expectedErr.Error() {
+			t.Fatalf("%s error returns %q, want %q", fn, err, expectedErr)
+		}
+		return
+	}
+	if expectedErr != nil {
+		t.Fatalf("%s should returns error, want %q", fn, expectedErr)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("%s returns \n%#v\nwant \n%#v", fn, actual, expected)
+	}
+}
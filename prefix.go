@@ -0,0 +1,127 @@
+package qstring
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// DecodeAt is URL-decodes the subtree of the query string under the key path.
+//
+// The key path is specified in dot form (e.g. "filter.range")
+// or bracket form (e.g. "filter[range]").
+// For example, DecodeAt("filter[status]=open&page[size]=10", "filter", &f)
+// decodes only "status=open" into f.
+//
+// The third argument supports the same types as the struct fields of Decode.
+// Nothing is decoded if the key path does not exist in the query string.
+func DecodeAt(s string, path string, v interface{}) error {
+	return NewDecoder().DecodeAt(s, path, v)
+}
+
+// EncodeAt returns the URL-encoded query string with the value under the key path.
+//
+// The key path is specified in the same form as DecodeAt.
+// For example, EncodeAt("filter", f) returns "filter[status]=open".
+// The argument supports the same types as the struct fields of Encode.
+func EncodeAt(path string, v interface{}) (string, error) {
+	return NewEncoder().EncodeAt(path, v)
+}
+
+// DecodeAt is URL-decodes the subtree of the query string under the key path like DecodeAt.
+func (dec *Decoder) DecodeAt(s string, path string, v interface{}) error {
+	d := decoder{config: dec.config, query: s}
+	return d.decodeAt(path, v)
+}
+
+// EncodeAt returns the URL-encoded query string with the value under the key path like EncodeAt.
+func (enc *Encoder) EncodeAt(path string, v interface{}) (string, error) {
+	e := encoder{config: enc.config}
+	return e.encodeAt(path, v)
+}
+
+func (d *decoder) decodeAt(path string, v interface{}) error {
+	keys, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &invalidDecodeError{reflect.TypeOf(v)}
+	}
+
+	valueMap, err := d.createIntermediateStruct()
+	if err != nil {
+		return err
+	}
+
+	uv, ok := lookupPath(valueMap, keys)
+	if !ok {
+		return nil
+	}
+
+	rv = rv.Elem()
+	if err := d.setTypeVlaue(rv.Type(), rv, uv, tagOption{}); err != nil {
+		return wrapFieldError(bracketPath(keys), err)
+	}
+	return nil
+}
+
+// lookupPath returns the value under the keys.
+// The index of the simple array values can be specified as the last key.
+func lookupPath(valueMap urlValueMap, keys []string) (urlValue, bool) {
+	uv, ok := valueMap[keys[0]]
+	if !ok {
+		return urlValue{}, false
+	}
+
+	for i, k := range keys[1:] {
+		if uv.hasChild() {
+			if uv, ok = uv.child[k]; !ok {
+				return urlValue{}, false
+			}
+			continue
+		}
+
+		if i != len(keys)-2 {
+			return urlValue{}, false
+		}
+		if uv.indexed != nil {
+			cuv, ok := uv.indexed[k]
+			return cuv, ok
+		}
+		idx, ok := pathIndex(k, len(uv.values))
+		if !ok {
+			return urlValue{}, false
+		}
+		return urlValue{key: k, values: []string{uv.values[idx]}, isString: true}, true
+	}
+	return uv, true
+}
+
+func (e *encoder) encodeAt(path string, v interface{}) (string, error) {
+	keys, err := parsePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	rv, err := e.indirect(v)
+	if err != nil {
+		return "", err
+	}
+
+	e.v = make(url.Values)
+	if err := e.encodeByType(bracketPath(keys), rv, tagOption{}); err != nil {
+		return "", err
+	}
+	return strings.Join(e.params(), "&"), nil
+}
+
+// bracketPath converts `[]string{"a", "b", "0"}` to `a[b][0]`
+func bracketPath(keys []string) string {
+	if len(keys) == 1 {
+		return keys[0]
+	}
+	return keys[0] + "[" + strings.Join(keys[1:], "][") + "]"
+}
@@ -0,0 +1,110 @@
+package qstring_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestDecodeAt(t *testing.T) {
+	type rng struct {
+		Min int `qstring:"min"`
+		Max int `qstring:"max"`
+	}
+	type filter struct {
+		Status string   `qstring:"status"`
+		Tags   []string `qstring:"tags"`
+		Range  *rng     `qstring:"range"`
+	}
+
+	q := "filter[status]=open&filter[tags][]=a&filter[tags][]=b&filter[range][min]=1&filter[range][max]=9&page[size]=10&sort=name"
+
+	testCases := []struct {
+		name     string
+		q        string
+		path     string
+		v        interface{}
+		expected interface{}
+		err      error
+	}{
+		{name: "struct", q: q, path: "filter", v: &filter{},
+			expected: &filter{Status: "open", Tags: []string{"a", "b"}, Range: &rng{Min: 1, Max: 9}}},
+		{name: "dot form", q: q, path: "filter.range", v: &rng{}, expected: &rng{Min: 1, Max: 9}},
+		{name: "bracket form", q: q, path: "filter[range]", v: &rng{}, expected: &rng{Min: 1, Max: 9}},
+		{name: "map", q: q, path: "page", v: &qstring.Q{}, expected: &qstring.Q{"size": "10"}},
+		{name: "typed map", q: q, path: "filter.range", v: &map[string]int{}, expected: &map[string]int{"min": 1, "max": 9}},
+		{name: "slice", q: q, path: "filter.tags", v: &[]string{}, expected: &[]string{"a", "b"}},
+		{name: "slice index", q: q, path: "filter.tags.1", v: new(string), expected: stringP("b")},
+		{name: "scalar", q: q, path: "page.size", v: new(int), expected: intP(10)},
+		{name: "slice of structs", q: "items[0][min]=1&items[1][min]=2", path: "items", v: &[]rng{}, expected: &[]rng{{Min: 1}, {Min: 2}}},
+		{name: "not found", q: q, path: "filter.no", v: &filter{}, expected: &filter{}},
+		{name: "not found index", q: q, path: "filter.tags.2", v: new(string), expected: new(string)},
+		{name: "sparse index", q: "ids[3]=c&ids[1]=a", path: "ids.3", v: new(string), expected: stringP("c")},
+		{name: "sparse slice", q: "ids[3]=c&ids[1]=a", path: "ids", v: &[]string{}, expected: &[]string{"a", "c"}},
+		{name: "invalid path", q: q, path: "filter..range", v: &rng{}, err: fmt.Errorf(`invalid path "filter..range"`)},
+		{name: "not pointer", q: q, path: "filter", v: filter{}, err: fmt.Errorf("non-pointer is not supported")},
+		{name: "not assign value", q: "filter[range][min]=x", path: "filter", v: &filter{}, err: fmt.Errorf(`"x" can not be assign to int`)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := qstring.DecodeAt(tc.q, tc.path, tc.v)
+			assertResult(t, "DecodeAt()", tc.v, err, tc.expected, tc.err)
+		})
+	}
+
+	t.Run("field error key", func(t *testing.T) {
+		err := qstring.DecodeAt("filter[range][min]=x", "filter", &filter{})
+		var fe *qstring.FieldError
+		if !errors.As(err, &fe) || fe.Key != "filter[range][min]" {
+			t.Errorf("DecodeAt() error = %#v, want the key filter[range][min]", err)
+		}
+	})
+}
+
+func TestEncodeAt(t *testing.T) {
+	type filter struct {
+		Status string   `qstring:"status"`
+		Tags   []string `qstring:"tags,omitempty"`
+	}
+
+	testCases := []struct {
+		name     string
+		path     string
+		v        interface{}
+		expected string
+		err      error
+	}{
+		{name: "struct", path: "filter", v: filter{Status: "open", Tags: []string{"a"}}, expected: "filter%5Bstatus%5D=open&filter%5Btags%5D%5B0%5D=a"},
+		{name: "struct pointer", path: "filter", v: &filter{Status: "open"}, expected: "filter%5Bstatus%5D=open"},
+		{name: "nested path", path: "query.filter", v: filter{Status: "open"}, expected: "query%5Bfilter%5D%5Bstatus%5D=open"},
+		{name: "map", path: "page", v: qstring.Q{"size": "10", "number": "2"}, expected: "page%5Bnumber%5D=2&page%5Bsize%5D=10"},
+		{name: "slice", path: "ids", v: []int{3, 1}, expected: "ids%5B0%5D=3&ids%5B1%5D=1"},
+		{name: "scalar", path: "page[size]", v: 10, expected: "page%5Bsize%5D=10"},
+		{name: "invalid path", path: "", v: filter{}, err: fmt.Errorf(`invalid path ""`)},
+		{name: "nil", path: "filter", v: nil, err: fmt.Errorf("nil is not supported")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := qstring.EncodeAt(tc.path, tc.v)
+			assertResult(t, "EncodeAt()", actual, err, tc.expected, tc.err)
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		in := filter{Status: "closed", Tags: []string{"x", "y"}}
+		q, err := qstring.EncodeAt("filter", in)
+		if err != nil {
+			t.Fatalf("EncodeAt() error = %v", err)
+		}
+
+		var out filter
+		if err := qstring.DecodeAt(q+"&sort=name", "filter", &out); err != nil {
+			t.Fatalf("DecodeAt() error = %v", err)
+		}
+		assertResult(t, "DecodeAt()", out, nil, in, nil)
+	})
+}
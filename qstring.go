@@ -1,32 +1,161 @@
 package qstring
 
+import (
+	"net/url"
+	"time"
+)
+
 // Q is the type of the query string parameters.
 type Q map[string]interface{}
 
 // S is a type of query string in slice format.
 type S []interface{}
 
+// Encoder encodes values to query strings with the options.
+type Encoder struct {
+	config config
+}
+
+// NewEncoder returns an Encoder with the options.
+func NewEncoder(opts ...Option) *Encoder {
+	return &Encoder{config: newConfig(opts)}
+}
+
+// Encode returns the URL-encoded query string like Encode.
+func (enc *Encoder) Encode(v interface{}) (string, error) {
+	e := encoder{config: enc.config}
+	return e.encode(v)
+}
+
+// EncodeValues returns the value as url.Values like EncodeValues.
+func (enc *Encoder) EncodeValues(v interface{}) (url.Values, error) {
+	e := encoder{config: enc.config}
+	return e.values(v)
+}
+
+// Decoder decodes query strings with the options.
+type Decoder struct {
+	config config
+}
+
+// NewDecoder returns a Decoder with the options.
+func NewDecoder(opts ...Option) *Decoder {
+	return &Decoder{config: newConfig(opts)}
+}
+
+// Decode is URL-decodes query string like Decode.
+func (dec *Decoder) Decode(s string, v interface{}) error {
+	d := decoder{config: dec.config, query: s}
+	return d.decode(v)
+}
+
+// DecodeValues is decodes url.Values like DecodeValues.
+func (dec *Decoder) DecodeValues(values url.Values, v interface{}) error {
+	if values == nil {
+		values = url.Values{}
+	}
+	d := decoder{config: dec.config, values: values}
+	return d.decode(v)
+}
+
 // Encode returns the URL-encoded query string.
 //
 // The argument supports
-// string type, struct, map type where the key is a string.
+// string type, struct, map type where the key is a string, OrderedQ.
 //
-// The struct needs to specify the "qstring" tag in the public field.
+// If the argument is OrderedQ, the keys are output in the order of OrderedQ.
+// Otherwise, they are sorted by key.
+//
+// The struct needs to specify the "qstring" tag in the public field,
+// or use an Encoder with WithNaming for the fields without the tag.
+// Use WithInlineEmbedded to encode the fields of the embedded struct as the fields of the struct.
+// Use an Encoder with WithTagNames to read the other tags such as "url" and "json".
+// The fields with the tag "-" are skipped.
 // If you don't want to output zero-value, Please specify option "omitempty" in the tag.
+// The fields with option "sealed" are encrypted, it requires an Encoder with WithSealKeys.
 func Encode(v interface{}) (string, error) {
-	e := encoder{}
-	return e.encode(v)
+	return NewEncoder().Encode(v)
 }
 
 // Decode is URL-decodes query string.
 //
 // The second argument supports
-// string type, array, slice, struct, map type where the key is a string.
+// string type, array, slice, struct, map type where the key is a string, OrderedQ.
 //
-// The struct needs to specify the "qstring" tag in the public field.
+// The struct needs to specify the "qstring" tag in the public field,
+// or use a Decoder with WithNaming for the fields without the tag.
+// Use WithInlineEmbedded to decode the fields of the embedded struct as the fields of the struct.
+// Use a Decoder with WithTagNames to read the other tags such as "url" and "json".
+// The fields with option "sealed" are decrypted, it requires a Decoder with WithSealKeys.
 func Decode(s string, v interface{}) error {
-	d := decoder{query: s}
-	return d.decode(v)
+	return NewDecoder().Decode(s, v)
+}
+
+// EncodeValues returns the value as url.Values.
+//
+// The argument supports the same types as Encode.
+// The keys of the nested values are flattened in bracket form (e.g. "key[a][0]").
+func EncodeValues(v interface{}) (url.Values, error) {
+	return NewEncoder().EncodeValues(v)
+}
+
+// DecodeValues is decodes url.Values.
+//
+// The keys in bracket form (e.g. "key[a][0]") are decoded as nested values.
+// The second argument supports the same types as Decode.
+func DecodeValues(values url.Values, v interface{}) error {
+	return NewDecoder().DecodeValues(values, v)
+}
+
+// FromValues returns url.Values as Q type.
+//
+// The keys in bracket form (e.g. "key[a][0]") are converted to nested Q and S.
+func FromValues(values url.Values) (Q, error) {
+	var v Q
+	err := DecodeValues(values, &v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Values returns q as url.Values.
+//
+// The keys of the nested values are flattened in bracket form (e.g. "key[a][0]").
+// The keys whose values are not supported by Encode are omitted,
+// use EncodeValues to get the error.
+func (q Q) Values() url.Values {
+	values := make(url.Values)
+	for k, v := range q {
+		vs, err := EncodeValues(Q{k: v})
+		if err != nil {
+			continue
+		}
+		for vk, vv := range vs {
+			values[vk] = vv
+		}
+	}
+	return values
+}
+
+// Sign returns the URL-encoded query string
+// with the expiry and signature parameters appended.
+//
+// The signature is HMAC-SHA256 over the canonical form of the query string (see Canonicalize).
+// The expiry is the Unix time after ttl, it is omitted if ttl is zero or less.
+// Use NewSigner to change the hash function or the parameter keys.
+func Sign(v interface{}, key []byte, ttl time.Duration) (string, error) {
+	return NewSigner(key).SignValue(v, ttl)
+}
+
+// VerifyDecode verifies the signature and expiry of the query string signed by Sign,
+// and then decodes it.
+//
+// It returns ErrMissingSignature, ErrInvalidSignature if the query string has been tampered with,
+// or ErrSignatureExpired if the signature has expired.
+// Nothing is decoded if the verification fails.
+func VerifyDecode(query string, key []byte, v interface{}) error {
+	return NewSigner(key).VerifyDecode(query, v)
 }
 
 // DecodeToString returns the URL-decoded query string.
@@ -49,6 +178,17 @@ func DecodeToMap(s string) (Q, error) {
 	return v, nil
 }
 
+// DecodeToOrderedMap returns the URL-decoded query string as OrderedQ type.
+// The order of the keys in the query string is preserved.
+func DecodeToOrderedMap(s string) (*OrderedQ, error) {
+	v := &OrderedQ{}
+	err := Decode(s, v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
 // DecodeToSlice returns the URL-decoded query string as slice type
 func DecodeToSlice(s string) ([]string, error) {
 	var v []string
@@ -0,0 +1,197 @@
+package qstring
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+// DefaultMaxBodySize is the default maximum size of the request body read by DecodeRequest.
+const DefaultMaxBodySize = 10 << 20 // 10 MB
+
+const formContentType = "application/x-www-form-urlencoded"
+
+// Precedence is the rule when both the URL query and the form body define the same key.
+type Precedence int
+
+const (
+	// PreferQuery uses the value of the URL query.
+	PreferQuery Precedence = iota
+	// PreferBody uses the value of the form body.
+	PreferBody
+	// MergeBoth uses both values, the values of the form body come first like http.Request.Form.
+	MergeBoth
+)
+
+// RequestOption is an option for DecodeRequest.
+type RequestOption func(*requestConfig)
+
+type requestConfig struct {
+	precedence  Precedence
+	maxBodySize int64
+	decoder     *Decoder
+}
+
+// WithPrecedence sets the rule when both the URL query and the form body define the same key.
+// The default is PreferQuery.
+func WithPrecedence(p Precedence) RequestOption {
+	return func(c *requestConfig) {
+		c.precedence = p
+	}
+}
+
+// WithMaxBodySize sets the maximum size of the form body in bytes.
+// The default is DefaultMaxBodySize.
+func WithMaxBodySize(n int64) RequestOption {
+	return func(c *requestConfig) {
+		c.maxBodySize = n
+	}
+}
+
+// WithRequestDecoder sets the Decoder used by DecodeRequest, such as the one with WithLimits.
+// The default is a Decoder without options.
+func WithRequestDecoder(dec *Decoder) RequestOption {
+	return func(c *requestConfig) {
+		c.decoder = dec
+	}
+}
+
+// RequestError is an error caused by the content of the request.
+//
+// It should be responded to the client as 400 Bad Request.
+type RequestError struct {
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the HTTP status code for the error.
+func (e *RequestError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+var errBodyTooLarge = errors.New("request body too large")
+
+// DecodeRequest is decodes the URL query and
+// the "application/x-www-form-urlencoded" body of the request.
+//
+// The second argument supports the same types as Decode.
+// The keys are compared in the raw form (e.g. "key[a]"), and
+// if both define the same key, the value is chosen by the precedence.
+// The form body is stored in r.PostForm as http.Request.ParseForm does,
+// and r.Body is restored for the handlers that read it again.
+//
+// The errors caused by the request are returned as *RequestError.
+func DecodeRequest(r *http.Request, v interface{}, opts ...RequestOption) error {
+	c := requestConfig{
+		precedence:  PreferQuery,
+		maxBodySize: DefaultMaxBodySize,
+		decoder:     NewDecoder(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	query, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		return &RequestError{err}
+	}
+
+	body, err := readFormBody(r, c.maxBodySize)
+	if err != nil {
+		return &RequestError{err}
+	}
+
+	err = c.decoder.DecodeValues(mergeValues(query, body, c.precedence), v)
+	if err == nil {
+		return nil
+	}
+
+	if isArgumentError(err) {
+		return err
+	}
+	return &RequestError{err}
+}
+
+// isArgumentError reports whether the error is caused by the argument of decoding, not by the request
+func isArgumentError(err error) bool {
+	var ide *invalidDecodeError
+	var ute *unsupportedTypeError
+	return errors.As(err, &ide) || errors.As(err, &ute)
+}
+
+func readFormBody(r *http.Request, maxBodySize int64) (url.Values, error) {
+	// the body has already been read by http.Request.ParseForm
+	if r.PostForm != nil {
+		return r.PostForm, nil
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return nil, nil
+	}
+
+	ct, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || ct != formContentType {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > maxBodySize {
+		return nil, errBodyTooLarge
+	}
+
+	// restore the body for the handlers that read it again
+	r.Body = io.NopCloser(bytes.NewReader(b))
+
+	values, err := url.ParseQuery(string(b))
+	if err != nil {
+		return nil, err
+	}
+	// same as http.Request.ParseForm, it uses the values without reading the body again
+	r.PostForm = values
+	return values, nil
+}
+
+func mergeValues(query, body url.Values, p Precedence) url.Values {
+	merged := make(url.Values, len(query)+len(body))
+
+	switch p {
+	case PreferBody:
+		copyValues(merged, query)
+		copyValues(merged, body)
+	case MergeBoth:
+		copyValues(merged, body)
+		for k, vs := range query {
+			merged[k] = append(merged[k], vs...)
+		}
+	default:
+		copyValues(merged, body)
+		copyValues(merged, query)
+	}
+	return merged
+}
+
+func copyValues(dst, src url.Values) {
+	for k, vs := range src {
+		dst[k] = append([]string{}, vs...)
+	}
+}
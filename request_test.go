@@ -0,0 +1,100 @@
+package qstring_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestDecodeRequest(t *testing.T) {
+	type s struct {
+		ID   int      `qstring:"id"`
+		Name string   `qstring:"name"`
+		Tags []string `qstring:"tags"`
+	}
+
+	newRequest := func(method, target, body string) *http.Request {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+		}
+		return r
+	}
+
+	testCases := []struct {
+		name     string
+		r        *http.Request
+		opts     []qstring.RequestOption
+		err      error
+		expected s
+	}{
+		{name: "query only", r: newRequest(http.MethodGet, "/?id=1&name=a", ""), expected: s{ID: 1, Name: "a"}},
+		{name: "body only", r: newRequest(http.MethodPost, "/", "id=2&tags[]=x&tags[]=y"), expected: s{ID: 2, Tags: []string{"x", "y"}}},
+		{name: "prefer query", r: newRequest(http.MethodPost, "/?id=1", "id=2&name=b"), expected: s{ID: 1, Name: "b"}},
+		{name: "prefer body", r: newRequest(http.MethodPost, "/?id=1&name=a", "id=2"),
+			opts: []qstring.RequestOption{qstring.WithPrecedence(qstring.PreferBody)}, expected: s{ID: 2, Name: "a"}},
+		{name: "merge both", r: newRequest(http.MethodPost, "/?tags[]=q", "tags[]=b"),
+			opts: []qstring.RequestOption{qstring.WithPrecedence(qstring.MergeBoth)}, expected: s{Tags: []string{"b", "q"}}},
+		{name: "body of get request is ignored", r: newRequest(http.MethodGet, "/?id=1", "name=b"), expected: s{ID: 1}},
+		{name: "body too large", r: newRequest(http.MethodPost, "/", "id=123456"),
+			opts: []qstring.RequestOption{qstring.WithMaxBodySize(5)}, err: fmt.Errorf("request body too large")},
+		{name: "invalid value", r: newRequest(http.MethodGet, "/?id=a", ""), err: fmt.Errorf(`"a" can not be assign to int`)},
+		{name: "invalid body", r: newRequest(http.MethodPost, "/", "id=%zz"), err: errors.New(`invalid URL escape "%zz"`)},
+		{name: "limits of the decoder", r: newRequest(http.MethodPost, "/?id=1", "name=b&tags[]=x"),
+			opts: []qstring.RequestOption{qstring.WithRequestDecoder(qstring.NewDecoder(qstring.WithLimits(qstring.Limits{MaxParams: 2})))},
+			err:  fmt.Errorf("query exceeds the limit of MaxParams (2)")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := s{}
+			err := qstring.DecodeRequest(tc.r, &v, tc.opts...)
+			if err != nil {
+				var re *qstring.RequestError
+				if !errors.As(err, &re) || re.StatusCode() != http.StatusBadRequest {
+					t.Errorf("DecodeRequest() should returns *qstring.RequestError, got %#v", err)
+				}
+			}
+			assertResult(t, "DecodeRequest()", v, err, tc.expected, tc.err)
+		})
+	}
+
+	t.Run("parsed form", func(t *testing.T) {
+		r := newRequest(http.MethodPost, "/?name=a", "id=3")
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		v := s{}
+		err := qstring.DecodeRequest(r, &v)
+		assertResult(t, "DecodeRequest()", v, err, s{ID: 3, Name: "a"}, nil)
+	})
+
+	t.Run("parse form after decoding", func(t *testing.T) {
+		r := newRequest(http.MethodPost, "/?name=a", "id=3&tags[]=x")
+		v := s{}
+		err := qstring.DecodeRequest(r, &v)
+		assertResult(t, "DecodeRequest()", v, err, s{ID: 3, Name: "a", Tags: []string{"x"}}, nil)
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		assertResult(t, "FormValue()", []string{r.FormValue("id"), r.FormValue("name"), r.PostFormValue("tags[]")}, nil, []string{"3", "a", "x"}, nil)
+
+		b, err := io.ReadAll(r.Body)
+		assertResult(t, "ReadAll()", string(b), err, "id=3&tags[]=x", nil)
+	})
+
+	t.Run("invalid argument", func(t *testing.T) {
+		err := qstring.DecodeRequest(newRequest(http.MethodGet, "/?id=1", ""), s{})
+		var re *qstring.RequestError
+		if err == nil || errors.As(err, &re) {
+			t.Errorf("DecodeRequest() should returns non *qstring.RequestError, got %#v", err)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package qstring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+var errNoSealKey = errors.New("no seal key is configured")
+
+// SealKey is a key for the fields with the "sealed" option.
+type SealKey struct {
+	// ID identifies the key in the sealed value, up to 255 bytes.
+	ID string
+	// Key is the AES key, either 16, 24, or 32 bytes.
+	Key []byte
+}
+
+// WithSealKeys sets the keys for the fields with the "sealed" option.
+//
+// The value of the sealed field is encrypted with AES-GCM under the first key,
+// and output as base64url with the ID of the key.
+// When decoding, the key is chosen by the ID, so the old keys can be listed after the first one
+// to keep decoding the values sealed before the rotation.
+// The value is bound to its full key, so a value moved to another key fails to decode.
+func WithSealKeys(keys ...SealKey) Option {
+	return func(c *config) {
+		c.sealKeys = keys
+	}
+}
+
+// unsealError is an error when the sealed value cannot be decrypted
+type unsealError struct {
+	key string
+}
+
+func (e *unsealError) Error() string {
+	return `the value of "` + e.key + `" cannot be unsealed`
+}
+
+// seal encrypts the value, the full key of the query string in bracket form (e.g. "kids[0][uid]")
+// is authenticated as additional data, so the value cannot be moved to another key.
+// The output is base64url of `len(id) | id | nonce | ciphertext`.
+func (c *config) seal(key, value string) (string, error) {
+	if len(c.sealKeys) == 0 {
+		return "", errNoSealKey
+	}
+	sk := c.sealKeys[0]
+	if len(sk.ID) > 255 {
+		return "", errors.New("seal key ID is too long")
+	}
+
+	aead, err := newAEAD(sk.Key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	b := make([]byte, 0, 1+len(sk.ID)+len(nonce)+len(value)+aead.Overhead())
+	b = append(b, byte(len(sk.ID)))
+	b = append(b, sk.ID...)
+	b = append(b, nonce...)
+	b = aead.Seal(b, nonce, []byte(value), []byte(key))
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// unseal decrypts the value sealed by seal
+func (c *config) unseal(key, value string) (string, error) {
+	if len(c.sealKeys) == 0 {
+		return "", errNoSealKey
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(b) == 0 || len(b) < 1+int(b[0]) {
+		return "", &unsealError{key}
+	}
+	id := string(b[1 : 1+b[0]])
+	b = b[1+b[0]:]
+
+	for _, sk := range c.sealKeys {
+		if sk.ID != id {
+			continue
+		}
+
+		aead, err := newAEAD(sk.Key)
+		if err != nil {
+			return "", err
+		}
+		if len(b) < aead.NonceSize() {
+			return "", &unsealError{key}
+		}
+		plain, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], []byte(key))
+		if err != nil {
+			return "", &unsealError{key}
+		}
+		return string(plain), nil
+	}
+	return "", &unsealError{key}
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
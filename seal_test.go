@@ -0,0 +1,134 @@
+package qstring_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestSealed(t *testing.T) {
+	type s struct {
+		UID   int     `qstring:"uid,sealed"`
+		Email *string `qstring:"email,sealed,omitempty"`
+		Page  int     `qstring:"page"`
+	}
+
+	oldKey := qstring.SealKey{ID: "k1", Key: []byte("0123456789abcdef")}
+	newKey := qstring.SealKey{ID: "k2", Key: []byte("fedcba9876543210fedcba9876543210")}
+
+	enc := qstring.NewEncoder(qstring.WithSealKeys(oldKey))
+	encoded, err := enc.Encode(s{UID: 123, Email: stringP("a@example.com"), Page: 2})
+	if err != nil {
+		t.Fatalf("Encode() should not returns error, got %q", err)
+	}
+
+	values, _ := url.ParseQuery(encoded)
+	if values.Get("page") != "2" {
+		t.Errorf("page returns %q, want %q", values.Get("page"), "2")
+	}
+	for _, k := range []string{"uid", "email"} {
+		v := values.Get(k)
+		if v == "" || strings.Contains(v, "123") || strings.Contains(v, "example") {
+			t.Errorf("%s should be sealed, got %q", k, v)
+		}
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		v := s{}
+		err := qstring.NewDecoder(qstring.WithSealKeys(oldKey)).Decode(encoded, &v)
+		assertResult(t, "Decode()", v, err, s{UID: 123, Email: stringP("a@example.com"), Page: 2}, nil)
+	})
+
+	t.Run("decode after rotation", func(t *testing.T) {
+		v := s{}
+		err := qstring.NewDecoder(qstring.WithSealKeys(newKey, oldKey)).Decode(encoded, &v)
+		assertResult(t, "Decode()", v, err, s{UID: 123, Email: stringP("a@example.com"), Page: 2}, nil)
+	})
+
+	t.Run("unknown key id", func(t *testing.T) {
+		err := qstring.NewDecoder(qstring.WithSealKeys(newKey)).Decode(encoded, &s{})
+		assertResult(t, "Decode()", nil, err, nil, errors.New(`the value of "uid" cannot be unsealed`))
+	})
+
+	t.Run("swapped field", func(t *testing.T) {
+		q := "uid=" + values.Get("email")
+		err := qstring.NewDecoder(qstring.WithSealKeys(oldKey)).Decode(q, &s{})
+		assertResult(t, "Decode()", nil, err, nil, errors.New(`the value of "uid" cannot be unsealed`))
+	})
+
+	t.Run("swapped elements", func(t *testing.T) {
+		type kid struct {
+			User string `qstring:"u,sealed"`
+		}
+		type p struct {
+			Kids []kid `qstring:"kids"`
+		}
+
+		q, err := enc.Encode(p{Kids: []kid{{User: "alice"}, {User: "bob"}}})
+		if err != nil {
+			t.Fatalf("Encode() should not returns error, got %q", err)
+		}
+		kv, _ := url.ParseQuery(q)
+
+		dec := qstring.NewDecoder(qstring.WithSealKeys(oldKey))
+		v := p{}
+		err = dec.Decode(q, &v)
+		assertResult(t, "Decode()", v, err, p{Kids: []kid{{User: "alice"}, {User: "bob"}}}, nil)
+
+		swapped := url.Values{
+			"kids[0][u]": {kv.Get("kids[1][u]")},
+			"kids[1][u]": {kv.Get("kids[0][u]")},
+		}
+		err = dec.DecodeValues(swapped, &p{})
+		assertResult(t, "DecodeValues()", nil, err, nil, errors.New(`the value of "kids[0][u]" cannot be unsealed`))
+	})
+
+	t.Run("moved under another parent", func(t *testing.T) {
+		type p struct {
+			A s `qstring:"a"`
+			B s `qstring:"b"`
+		}
+		q, err := enc.Encode(p{A: s{UID: 1}})
+		if err != nil {
+			t.Fatalf("Encode() should not returns error, got %q", err)
+		}
+		pv, _ := url.ParseQuery(q)
+
+		err = qstring.NewDecoder(qstring.WithSealKeys(oldKey)).Decode("b[uid]="+pv.Get("a[uid]"), &p{})
+		assertResult(t, "Decode()", nil, err, nil, errors.New(`the value of "b[uid]" cannot be unsealed`))
+	})
+
+	t.Run("plaintext", func(t *testing.T) {
+		err := qstring.NewDecoder(qstring.WithSealKeys(oldKey)).Decode("uid=123", &s{})
+		assertResult(t, "Decode()", nil, err, nil, errors.New(`the value of "uid" cannot be unsealed`))
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		_, err := qstring.Encode(s{UID: 1})
+		assertResult(t, "Encode()", nil, err, nil, errors.New("no seal key is configured"))
+		err = qstring.Decode(encoded, &s{})
+		assertResult(t, "Decode()", nil, err, nil, errors.New("no seal key is configured"))
+	})
+
+	t.Run("not single value", func(t *testing.T) {
+		type l struct {
+			IDs []string `qstring:"ids,sealed"`
+		}
+		_, err := enc.Encode(l{IDs: []string{"a", "b"}})
+		assertResult(t, "Encode()", nil, err, nil, errors.New("[]string is not supported"))
+	})
+
+	t.Run("encoded differently each time", func(t *testing.T) {
+		again, err := enc.Encode(s{UID: 123, Page: 2})
+		if err != nil {
+			t.Fatalf("Encode() should not returns error, got %q", err)
+		}
+		av, _ := url.ParseQuery(again)
+		if av.Get("uid") == values.Get("uid") {
+			t.Errorf("sealed values should use a random nonce")
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package qstring
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSignatureParam is the default key of the signature parameter.
+	DefaultSignatureParam = "signature"
+	// DefaultExpiresParam is the default key of the expiry parameter.
+	DefaultExpiresParam = "expires"
+)
+
+var (
+	// ErrMissingSignature is returned when the query has no signature.
+	ErrMissingSignature = errors.New("signature is missing")
+	// ErrInvalidSignature is returned when the signature does not match the query,
+	// which means the query has been tampered with.
+	ErrInvalidSignature = errors.New("signature is invalid")
+	// ErrSignatureExpired is returned when the signature is valid but has expired.
+	ErrSignatureExpired = errors.New("signature has expired")
+)
+
+// SignerOption is an option for NewSigner.
+type SignerOption func(*Signer)
+
+// WithHash sets the hash function of HMAC.
+// The default is sha256.New.
+func WithHash(h func() hash.Hash) SignerOption {
+	return func(s *Signer) {
+		s.hash = h
+	}
+}
+
+// WithSignatureParam sets the key of the signature parameter.
+// The default is DefaultSignatureParam.
+func WithSignatureParam(key string) SignerOption {
+	return func(s *Signer) {
+		s.signatureParam = key
+	}
+}
+
+// WithExpiresParam sets the key of the expiry parameter.
+// The default is DefaultExpiresParam.
+func WithExpiresParam(key string) SignerOption {
+	return func(s *Signer) {
+		s.expiresParam = key
+	}
+}
+
+// WithClock sets the function that returns the current time.
+// The default is time.Now.
+func WithClock(now func() time.Time) SignerOption {
+	return func(s *Signer) {
+		s.now = now
+	}
+}
+
+// WithSignerEncoder sets the Encoder used by SignValue, such as the one with WithSealKeys.
+// The default is an Encoder without options.
+func WithSignerEncoder(enc *Encoder) SignerOption {
+	return func(s *Signer) {
+		s.encoder = enc
+	}
+}
+
+// WithSignerDecoder sets the Decoder used by VerifyDecode, such as the one with WithLimits.
+// The default is a Decoder without options.
+func WithSignerDecoder(dec *Decoder) SignerOption {
+	return func(s *Signer) {
+		s.decoder = dec
+	}
+}
+
+// Signer signs and verifies query strings with HMAC over the canonical form.
+type Signer struct {
+	key            []byte
+	hash           func() hash.Hash
+	signatureParam string
+	expiresParam   string
+	now            func() time.Time
+	encoder        *Encoder
+	decoder        *Decoder
+}
+
+// NewSigner returns a Signer with the secret key.
+func NewSigner(key []byte, opts ...SignerOption) *Signer {
+	s := &Signer{
+		key:            key,
+		hash:           sha256.New,
+		signatureParam: DefaultSignatureParam,
+		expiresParam:   DefaultExpiresParam,
+		now:            time.Now,
+		encoder:        NewEncoder(),
+		decoder:        NewDecoder(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Sign returns the canonical form of the query string
+// with the expiry and signature parameters appended.
+//
+// The expiry is the Unix time after ttl, it is omitted if ttl is zero or less.
+// The existing signature and expiry parameters are replaced.
+func (s *Signer) Sign(query string, ttl time.Duration) (string, error) {
+	d := decoder{query: strings.TrimPrefix(query, que)}
+	params, err := d.parseQuery()
+	if err != nil {
+		return "", err
+	}
+
+	params = removeParam(removeParam(params, s.signatureParam), s.expiresParam)
+	if ttl > 0 {
+		expires := s.now().Add(ttl).Unix()
+		params = append(params, queryParam{key: s.expiresParam, value: strconv.FormatInt(expires, 10)})
+	}
+
+	canonical := canonicalizeParams(params)
+	sig := canonicalizeParams([]queryParam{{key: s.signatureParam, value: s.signature(canonical)}})
+	if canonical == "" {
+		return sig, nil
+	}
+	return canonical + "&" + sig, nil
+}
+
+// Verify checks the signature and expiry parameters of the query string.
+//
+// It returns ErrMissingSignature, ErrInvalidSignature or ErrSignatureExpired.
+// The signatures are compared in constant time.
+func (s *Signer) Verify(query string) error {
+	d := decoder{query: strings.TrimPrefix(query, que)}
+	params, err := d.parseQuery()
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	sigs := findParam(params, s.signatureParam)
+	if len(sigs) == 0 {
+		return ErrMissingSignature
+	}
+	if len(sigs) > 1 {
+		return ErrInvalidSignature
+	}
+
+	params = removeParam(params, s.signatureParam)
+	expected := s.signature(canonicalizeParams(params))
+	if !hmac.Equal([]byte(expected), []byte(sigs[0])) {
+		return ErrInvalidSignature
+	}
+
+	expires := findParam(params, s.expiresParam)
+	if len(expires) == 0 {
+		return nil
+	}
+	if len(expires) > 1 {
+		return ErrInvalidSignature
+	}
+	exp, err := strconv.ParseInt(expires[0], 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if s.now().Unix() > exp {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// SignValue encodes the value and signs it like Sign.
+//
+// The argument supports the same types as Encode.
+func (s *Signer) SignValue(v interface{}, ttl time.Duration) (string, error) {
+	canonical, err := canonicalEncode(s.encoder.config, v)
+	if err != nil {
+		return "", err
+	}
+	return s.Sign(canonical, ttl)
+}
+
+// VerifyDecode verifies the query string like Verify, and then decodes it.
+//
+// The signature and expiry parameters are not decoded.
+// The second argument supports the same types as Decode.
+func (s *Signer) VerifyDecode(query string, v interface{}) error {
+	if err := s.Verify(query); err != nil {
+		return err
+	}
+
+	d := decoder{query: strings.TrimPrefix(query, que)}
+	params, err := d.parseQuery()
+	if err != nil {
+		return err
+	}
+
+	values := make(url.Values)
+	for _, p := range removeParam(removeParam(params, s.signatureParam), s.expiresParam) {
+		values.Add(p.key, p.value)
+	}
+	return s.decoder.DecodeValues(values, v)
+}
+
+func (s *Signer) signature(canonical string) string {
+	mac := hmac.New(s.hash, s.key)
+	mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func findParam(params []queryParam, key string) []string {
+	var values []string
+	for _, p := range params {
+		if p.key == key {
+			values = append(values, p.value)
+		}
+	}
+	return values
+}
+
+func removeParam(params []queryParam, key string) []queryParam {
+	ret := make([]queryParam, 0, len(params))
+	for _, p := range params {
+		if p.key != key {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
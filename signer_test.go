@@ -0,0 +1,166 @@
+package qstring_test
+
+import (
+	"crypto/sha1"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+	key := []byte("secret")
+
+	t.Run("sign", func(t *testing.T) {
+		s := qstring.NewSigner(key, qstring.WithClock(clock))
+		actual, err := s.Sign("?b=2&a=x+y&signature=old", time.Minute)
+		if err != nil {
+			t.Fatalf("Sign() should not returns error, got %q", err)
+		}
+		if !strings.HasPrefix(actual, "a=x%20y&b=2&expires=1700000060&signature=") {
+			t.Errorf("Sign() returns %q", actual)
+		}
+		if err := s.Verify(actual); err != nil {
+			t.Errorf("Verify() should not returns error, got %q", err)
+		}
+	})
+
+	t.Run("sign without expiry", func(t *testing.T) {
+		s := qstring.NewSigner(key, qstring.WithSignatureParam("sig"), qstring.WithHash(sha1.New))
+		actual, err := s.Sign("a=1", 0)
+		if err != nil {
+			t.Fatalf("Sign() should not returns error, got %q", err)
+		}
+		if !strings.HasPrefix(actual, "a=1&sig=") || len(actual) != len("a=1&sig=")+40 {
+			t.Errorf("Sign() returns %q", actual)
+		}
+		if err := s.Verify(actual); err != nil {
+			t.Errorf("Verify() should not returns error, got %q", err)
+		}
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		s := qstring.NewSigner(key, qstring.WithClock(clock))
+		signed, err := s.Sign("a=1&b=2", time.Minute)
+		if err != nil {
+			t.Fatalf("Sign() should not returns error, got %q", err)
+		}
+		sig := signed[strings.Index(signed, "signature="):]
+
+		testCases := []struct {
+			name   string
+			q      string
+			signer *qstring.Signer
+			err    error
+		}{
+			{name: "valid", q: signed, signer: s},
+			{name: "reordered", q: sig + "&b=2&expires=1700000060&a=1", signer: s},
+			{name: "missing", q: "a=1&b=2&expires=1700000060", signer: s, err: qstring.ErrMissingSignature},
+			{name: "tampered value", q: strings.Replace(signed, "a=1", "a=2", 1), signer: s, err: qstring.ErrInvalidSignature},
+			{name: "tampered expiry", q: strings.Replace(signed, "expires=1700000060", "expires=1800000000", 1), signer: s, err: qstring.ErrInvalidSignature},
+			{name: "added param", q: signed + "&c=3", signer: s, err: qstring.ErrInvalidSignature},
+			{name: "multiple signatures", q: signed + "&" + sig, signer: s, err: qstring.ErrInvalidSignature},
+			{name: "other key", q: signed, signer: qstring.NewSigner([]byte("other"), qstring.WithClock(clock)), err: qstring.ErrInvalidSignature},
+			{name: "expired", q: signed, signer: qstring.NewSigner(key, qstring.WithClock(func() time.Time { return now.Add(time.Hour) })), err: qstring.ErrSignatureExpired},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				err := tc.signer.Verify(tc.q)
+				if !errors.Is(err, tc.err) {
+					t.Errorf("Verify() returns %v, want %v", err, tc.err)
+				}
+			})
+		}
+	})
+}
+
+func TestSign(t *testing.T) {
+	type s struct {
+		File string `qstring:"file"`
+		User int    `qstring:"user"`
+	}
+	key := []byte("secret")
+
+	signed, err := qstring.Sign(s{File: "a b.txt", User: 10}, key, time.Hour)
+	if err != nil {
+		t.Fatalf("Sign() should not returns error, got %q", err)
+	}
+	if !strings.HasPrefix(signed, "expires=") || !strings.Contains(signed, "&file=a%20b.txt&user=10&signature=") {
+		t.Errorf("Sign() returns %q", signed)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		v := s{}
+		err := qstring.VerifyDecode(signed, key, &v)
+		assertResult(t, "VerifyDecode()", v, err, s{File: "a b.txt", User: 10}, nil)
+
+		q := qstring.Q{}
+		err = qstring.VerifyDecode(signed, key, &q)
+		assertResult(t, "VerifyDecode()", q, err, qstring.Q{"file": "a b.txt", "user": "10"}, nil)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		v := s{}
+		err := qstring.VerifyDecode(strings.Replace(signed, "user=10", "user=11", 1), key, &v)
+		if !errors.Is(err, qstring.ErrInvalidSignature) {
+			t.Errorf("VerifyDecode() returns %v, want %v", err, qstring.ErrInvalidSignature)
+		}
+		if v != (s{}) {
+			t.Errorf("VerifyDecode() should not decode, got %#v", v)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired, err := qstring.Sign(s{File: "a"}, key, time.Nanosecond)
+		if err != nil {
+			t.Fatalf("Sign() should not returns error, got %q", err)
+		}
+		signer := qstring.NewSigner(key, qstring.WithClock(func() time.Time { return time.Now().Add(2 * time.Second) }))
+		err = signer.VerifyDecode(expired, &s{})
+		if !errors.Is(err, qstring.ErrSignatureExpired) {
+			t.Errorf("VerifyDecode() returns %v, want %v", err, qstring.ErrSignatureExpired)
+		}
+	})
+}
+
+func TestSigner_encoderDecoder(t *testing.T) {
+	type s struct {
+		UserID int
+		Tags   []string
+	}
+	key := []byte("secret")
+	signer := qstring.NewSigner(key,
+		qstring.WithSignerEncoder(qstring.NewEncoder(qstring.WithNaming(qstring.SnakeCase))),
+		qstring.WithSignerDecoder(qstring.NewDecoder(qstring.WithNaming(qstring.SnakeCase), qstring.WithLimits(qstring.Limits{MaxParams: 3}))),
+	)
+
+	signed, err := signer.SignValue(s{UserID: 10, Tags: []string{"a"}}, 0)
+	if err != nil {
+		t.Fatalf("SignValue() should not returns error, got %q", err)
+	}
+	if !strings.HasPrefix(signed, "tags%5B0%5D=a&user_id=10&signature=") {
+		t.Errorf("SignValue() returns %q", signed)
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		v := s{}
+		err := signer.VerifyDecode(signed, &v)
+		assertResult(t, "VerifyDecode()", v, err, s{UserID: 10, Tags: []string{"a"}}, nil)
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		signed, err := signer.SignValue(s{UserID: 10, Tags: []string{"a", "b", "c"}}, 0)
+		if err != nil {
+			t.Fatalf("SignValue() should not returns error, got %q", err)
+		}
+		var lerr *qstring.LimitExceededError
+		if err := signer.VerifyDecode(signed, &s{}); !errors.As(err, &lerr) || lerr.Limit != "MaxParams" {
+			t.Errorf("VerifyDecode() returns %v, want MaxParams exceeded", err)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package qstring
 
 import (
+	"net/url"
 	"reflect"
 	"strings"
 )
@@ -9,20 +10,106 @@ const (
 	tagName      = "qstring"
 	optSeparator = ","
 	omitempty    = "omitempty"
+	sealed       = "sealed"
+	inline       = "inline"
+	hexOpt       = "hex"
+	base64StdOpt = "base64std"
+	// boolOpt is the prefix of the option "bool=<true>|<false>"
+	boolOpt = "bool="
+	// aliasOpt is the prefix of the option "alias=<name>|<name>"
+	aliasOpt = "alias="
+	// valueSeparator separates the values of the option
+	valueSeparator = "|"
 )
 
 type tagOption struct {
 	omitempty bool
+	sealed    bool
+	// inline decodes and encodes the fields of the struct as the fields of the parent
+	inline bool
+	// bytes is the format of []byte and [N]byte
+	bytes bytesFormat
+	// boolTrue and boolFalse are the representations of bool
+	boolTrue  string
+	boolFalse string
+	// aliases is the other keys accepted by Decoder in order of priority
+	aliases []string
+	// duplicate and joinSeparator override the config of the duplicate keys
+	duplicate     DuplicatePolicy
+	joinSeparator string
+	// invalid is the unknown or malformed option, Encoder and Decoder return an error for it
+	invalid string
 }
 
-func parseTag(tag reflect.StructTag) (string, tagOption) {
-	s := tag.Get(tagName)
-	if idx := strings.Index(s, optSeparator); idx != -1 {
-		return s[:idx], tagOption{
-			omitempty: strings.Contains(s[idx:], omitempty),
+func parseTag(s string) (string, tagOption) {
+	idx := strings.Index(s, optSeparator)
+	if idx == -1 {
+		return s, tagOption{}
+	}
+
+	opt := tagOption{}
+	for _, o := range strings.Split(s[idx+1:], optSeparator) {
+		o = strings.TrimSpace(o)
+		if strings.HasPrefix(o, boolOpt) {
+			// an empty token is encoded as the key without value, which is true with WithPresenceAsTrue
+			vals := strings.Split(o[len(boolOpt):], valueSeparator)
+			if len(vals) != 2 || vals[0] == "" || vals[1] == "" {
+				opt.setInvalid(o)
+				continue
+			}
+			opt.boolTrue, opt.boolFalse = vals[0], vals[1]
+			continue
+		}
+		if strings.HasPrefix(o, dupOpt) {
+			policy, ok := duplicatePolicies[o[len(dupOpt):]]
+			if !ok {
+				opt.setInvalid(o)
+			}
+			opt.duplicate = policy
+			continue
+		}
+		if strings.HasPrefix(o, sepOpt) {
+			// the separator is percent-encoded to write "," and " " (e.g. "sep=%2C%20")
+			sep, err := url.PathUnescape(o[len(sepOpt):])
+			if err != nil {
+				opt.setInvalid(o)
+			}
+			opt.joinSeparator = sep
+			continue
+		}
+		if strings.HasPrefix(o, aliasOpt) {
+			for _, a := range strings.Split(o[len(aliasOpt):], valueSeparator) {
+				if a != "" {
+					opt.aliases = append(opt.aliases, a)
+				}
+			}
+			continue
+		}
+
+		switch o {
+		case omitempty:
+			opt.omitempty = true
+		case sealed:
+			opt.sealed = true
+		case inline:
+			opt.inline = true
+		case hexOpt:
+			opt.bytes = bytesHex
+		case base64StdOpt:
+			opt.bytes = bytesBase64Std
+		case "":
+		default:
+			opt.setInvalid(o)
 		}
 	}
-	return s, tagOption{}
+	return s[:idx], opt
+}
+
+// setInvalid keeps the first invalid option
+func (opt *tagOption) setInvalid(o string) {
+	if opt.invalid == "" {
+		opt.invalid = o
+	}
 }
 
 func isEmptyValue(rv reflect.Value) bool {
@@ -42,3 +129,52 @@ func isEmptyValue(rv reflect.Value) bool {
 	}
 	return false
 }
+
+// WithTagNames sets the keys of the struct tag to look up in order.
+// The first key present on the field is used, the default is "qstring".
+//
+// For example, WithTagNames("qstring", "url", "form", "json") reads the tags of
+// go-querystring, form binders and encoding/json when there is no "qstring" tag.
+// The options "omitempty", "inline" and the name "-" of those tags are understood,
+// and the other options are ignored. The unknown options of the "qstring" tag are errors.
+func WithTagNames(names ...string) Option {
+	return func(c *config) {
+		c.tagNames = names
+	}
+}
+
+// WithInlineEmbedded inlines the embedded structs without the key like encoding/json,
+// as if they have the option "inline". The exported fields of the unexported struct types are also inlined.
+// By default, the embedded structs are the same as the other fields.
+func WithInlineEmbedded() Option {
+	return func(c *config) {
+		c.inlineEmbedded = true
+	}
+}
+
+// lookupTag returns the value of the first tag present in the configured keys and the key
+func (c *config) lookupTag(tag reflect.StructTag) (string, string) {
+	if c.tagNames == nil {
+		return tag.Get(tagName), tagName
+	}
+	for _, name := range c.tagNames {
+		if s, ok := tag.Lookup(name); ok {
+			return s, name
+		}
+	}
+	return "", ""
+}
+
+// WithAliasHandler sets the func called when Decoder decodes a field
+// from its alias instead of its name (e.g. "q" of `qstring:"query,alias=q|search"`).
+// It is useful to count the requests that still use the deprecated names.
+//
+// If several keys of the field are present, the name takes priority,
+// and then the aliases in the order of the option. The others are ignored,
+// but fn is called for every alias present.
+// Encoder always uses the name.
+func WithAliasHandler(fn func(name, alias string)) Option {
+	return func(c *config) {
+		c.aliasHandler = fn
+	}
+}
@@ -0,0 +1,292 @@
+package qstring_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestWithTagNames(t *testing.T) {
+	type s struct {
+		Q       string `qstring:"q" url:"url_q" json:"json_q"`
+		URL     string `url:"u,omitempty" json:"json_u"`
+		Form    string `form:"f"`
+		JSON    string `json:"j,omitempty,string"`
+		Skipped string `url:"-" qstring:"skipped"`
+		None    string
+	}
+	v := s{Q: "1", URL: "2", Form: "3", JSON: "4", Skipped: "5", None: "6"}
+
+	testCases := []struct {
+		name     string
+		names    []string
+		expected string
+	}{
+		{name: "default", names: nil, expected: "q=1&skipped=5"},
+		{name: "single name", names: []string{"json"}, expected: "j=4&json_q=1&json_u=2"},
+		{name: "fallback chain", names: []string{"qstring", "url", "form", "json"}, expected: "f=3&j=4&q=1&skipped=5&u=2"},
+		{name: "skip in the first tag", names: []string{"url", "qstring"}, expected: "u=2&url_q=1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []qstring.Option
+			if tc.names != nil {
+				opts = append(opts, qstring.WithTagNames(tc.names...))
+			}
+
+			actual, err := qstring.NewEncoder(opts...).Encode(v)
+			assertResult(t, "Encode()", actual, err, tc.expected, nil)
+
+			var decoded s
+			err = qstring.NewDecoder(opts...).Decode("q=1&url_q=1&json_q=1&u=2&json_u=2&f=3&j=4&skipped=5&None=6", &decoded)
+			expected := s{}
+			switch tc.name {
+			case "default":
+				expected = s{Q: "1", Skipped: "5"}
+			case "single name":
+				expected = s{Q: "1", URL: "2", JSON: "4"}
+			case "fallback chain":
+				expected = s{Q: "1", URL: "2", Form: "3", JSON: "4", Skipped: "5"}
+			case "skip in the first tag":
+				expected = s{Q: "1", URL: "2"}
+			}
+			assertResult(t, "Decode()", decoded, err, expected, nil)
+		})
+	}
+
+	t.Run("omitempty of the foreign tag", func(t *testing.T) {
+		actual, err := qstring.NewEncoder(qstring.WithTagNames("url")).Encode(s{})
+		assertResult(t, "Encode()", actual, err, "url_q=", nil)
+	})
+}
+
+func TestParseTag_invalidOption(t *testing.T) {
+	type typo struct {
+		Name string `qstring:"name,omitemtpy"`
+	}
+	type bytesFormat struct {
+		Data []byte `qstring:"data,base64"`
+	}
+	type boolFormat struct {
+		Flag bool `qstring:"flag,bool=yes"`
+	}
+	type foreign struct {
+		ID int `json:"id,string"`
+	}
+
+	testCases := []struct {
+		name string
+		v    interface{}
+		err  error
+	}{
+		{name: "typo", v: &typo{}, err: fmt.Errorf(`invalid tag option "omitemtpy" of qstring_test.typo.Name`)},
+		{name: "unknown bytes format", v: &bytesFormat{}, err: fmt.Errorf(`invalid tag option "base64" of qstring_test.bytesFormat.Data`)},
+		{name: "malformed bool", v: &boolFormat{}, err: fmt.Errorf(`invalid tag option "bool=yes" of qstring_test.boolFormat.Flag`)},
+		{name: "option of the other tag", v: &foreign{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := qstring.WithTagNames("qstring", "json")
+			err := qstring.NewDecoder(opt).Decode("id=1", tc.v)
+			assertResult(t, "Decode()", nil, err, nil, tc.err)
+			_, err = qstring.NewEncoder(opt).Encode(tc.v)
+			assertResult(t, "Encode()", nil, err, nil, tc.err)
+		})
+	}
+}
+
+func TestInline(t *testing.T) {
+	type page struct {
+		Page int `json:"page"`
+		Size int `json:"size,omitempty"`
+	}
+	type sort struct {
+		Sort string `qstring:"sort"`
+	}
+	type s struct {
+		Page  page   `qstring:",inline"`
+		Sort  *sort  `qstring:",inline"`
+		Query string `qstring:"q"`
+	}
+
+	testCases := []struct {
+		name string
+		q    string
+		v    s
+	}{
+		{name: "with pointer", q: "page=2&size=10&sort=name&q=a", v: s{Page: page{Page: 2, Size: 10}, Sort: &sort{Sort: "name"}, Query: "a"}},
+		{name: "nil pointer", q: "page=2&q=a", v: s{Page: page{Page: 2}, Query: "a"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var decoded s
+			err := qstring.NewDecoder(qstring.WithTagNames("qstring", "json")).Decode(tc.q, &decoded)
+			assertResult(t, "Decode()", decoded, err, tc.v, nil)
+		})
+	}
+
+	t.Run("encode", func(t *testing.T) {
+		v := s{Page: page{Page: 2}, Query: "a"}
+		actual, err := qstring.NewEncoder(qstring.WithTagNames("qstring", "json")).Encode(v)
+		assertResult(t, "Encode()", actual, err, "page=2&q=a", nil)
+	})
+
+	t.Run("not struct", func(t *testing.T) {
+		_, err := qstring.Encode(struct {
+			Value string `qstring:",inline"`
+		}{})
+		assertResult(t, "Encode()", nil, err, nil, fmt.Errorf("string is not supported"))
+	})
+}
+
+type Paging struct {
+	Page int `qstring:"page"`
+	Size int `qstring:"size,omitempty"`
+}
+
+type sorting struct {
+	Sort  string `qstring:"sort"`
+	order string
+}
+
+type Node struct {
+	*Node
+	V string `qstring:"v"`
+}
+
+func TestInline_embedded(t *testing.T) {
+	type s struct {
+		Paging
+		sorting
+		Query string `qstring:"q"`
+	}
+	type pointer struct {
+		*Paging
+		Query string `qstring:"q"`
+	}
+	type named struct {
+		Paging `qstring:"paging"`
+	}
+
+	testCases := []struct {
+		name     string
+		q        string
+		v        interface{}
+		expected interface{}
+	}{
+		{name: "exported", q: "page=2&size=10&sort=name&q=a", v: &s{},
+			expected: &s{Paging: Paging{Page: 2, Size: 10}, sorting: sorting{Sort: "name"}, Query: "a"}},
+		{name: "pointer", q: "page=2&q=a", v: &pointer{}, expected: &pointer{Paging: &Paging{Page: 2}, Query: "a"}},
+		{name: "nil pointer", q: "q=a", v: &pointer{}, expected: &pointer{Query: "a"}},
+		{name: "named", q: "paging[page]=2&page=3", v: &named{}, expected: &named{Paging: Paging{Page: 2}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := qstring.NewDecoder(qstring.WithInlineEmbedded()).Decode(tc.q, tc.v)
+			assertResult(t, "Decode()", tc.v, err, tc.expected, nil)
+		})
+	}
+
+	t.Run("encode", func(t *testing.T) {
+		enc := qstring.NewEncoder(qstring.WithInlineEmbedded())
+		actual, err := enc.Encode(s{Paging: Paging{Page: 2}, sorting: sorting{Sort: "name", order: "asc"}, Query: "a"})
+		assertResult(t, "Encode()", actual, err, "page=2&q=a&sort=name", nil)
+
+		actual, err = enc.Encode(pointer{Query: "a"})
+		assertResult(t, "Encode()", actual, err, "q=a", nil)
+	})
+
+	t.Run("skipped by default", func(t *testing.T) {
+		v := s{}
+		err := qstring.Decode("page=2&sort=name&q=a", &v)
+		assertResult(t, "Decode()", v, err, s{Query: "a"}, nil)
+
+		actual, err := qstring.Encode(s{Paging: Paging{Page: 2}, Query: "a"})
+		assertResult(t, "Encode()", actual, err, "q=a", nil)
+	})
+
+	t.Run("recursive embedding", func(t *testing.T) {
+		type explicit struct {
+			Next *explicit `qstring:",inline"`
+			V    string    `qstring:"v"`
+		}
+
+		n := Node{}
+		err := qstring.NewDecoder(qstring.WithInlineEmbedded()).Decode("v=1", &n)
+		assertResult(t, "Decode()", n, err, Node{V: "1"}, nil)
+
+		e := explicit{}
+		err = qstring.Decode("v=1", &e)
+		assertResult(t, "Decode()", e, err, explicit{V: "1"}, nil)
+
+		err = qstring.NewDecoder(qstring.WithInlineEmbedded()).DecodeInto("v=1", &n, &Paging{})
+		assertResult(t, "DecodeInto()", nil, err, nil, nil)
+	})
+}
+
+func TestAlias(t *testing.T) {
+	type filter struct {
+		Status string `qstring:"status,alias=st"`
+	}
+	type s struct {
+		Query  string `qstring:"query,alias=q|search"`
+		Limit  int    `qstring:"limit,omitempty,alias=per_page"`
+		Filter filter `qstring:"filter,alias=f"`
+	}
+
+	testCases := []struct {
+		name     string
+		q        string
+		expected s
+		used     []string
+		err      error
+	}{
+		{name: "name", q: "query=a&limit=1", expected: s{Query: "a", Limit: 1}},
+		{name: "alias", q: "q=a&per_page=1", expected: s{Query: "a", Limit: 1}, used: []string{"query<-q", "limit<-per_page"}},
+		{name: "second alias", q: "search=a", expected: s{Query: "a"}, used: []string{"query<-search"}},
+		{name: "name takes priority", q: "search=c&q=b&query=a", expected: s{Query: "a"}, used: []string{"query<-q", "query<-search"}},
+		{name: "name and an alias", q: "query=a&per_page=1&limit=2", expected: s{Query: "a", Limit: 2}, used: []string{"limit<-per_page"}},
+		{name: "alias in order of the option", q: "search=c&q=b", expected: s{Query: "b"}, used: []string{"query<-q", "query<-search"}},
+		{name: "nested", q: "f[st]=open", expected: s{Filter: filter{Status: "open"}}, used: []string{"filter<-f", "status<-st"}},
+		{name: "error key", q: "per_page=x", err: fmt.Errorf(`"x" can not be assign to int`), used: []string{"limit<-per_page"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var used []string
+			dec := qstring.NewDecoder(qstring.WithAliasHandler(func(name, alias string) {
+				used = append(used, name+"<-"+alias)
+			}))
+
+			var actual s
+			err := dec.Decode(tc.q, &actual)
+			assertResult(t, "Decode()", actual, err, tc.expected, tc.err)
+			assertResult(t, "WithAliasHandler()", used, nil, tc.used, nil)
+		})
+	}
+
+	t.Run("field error key is the alias", func(t *testing.T) {
+		err := qstring.Decode("per_page=x", &s{})
+		var fe *qstring.FieldError
+		if !errors.As(err, &fe) || fe.Key != "per_page" {
+			t.Errorf("Decode() error = %#v, want the key per_page", err)
+		}
+	})
+
+	t.Run("encode uses the name", func(t *testing.T) {
+		actual, err := qstring.Encode(s{Query: "a", Filter: filter{Status: "open"}})
+		assertResult(t, "Encode()", actual, err, "filter%5Bstatus%5D=open&query=a", nil)
+	})
+
+	t.Run("aliases are claimed by DecodeInto", func(t *testing.T) {
+		dec := qstring.NewDecoder(qstring.WithDisallowUnclaimedKeys())
+		err := dec.DecodeInto("q=a&st=open", &s{}, &filter{})
+		assertResult(t, "DecodeInto()", nil, err, nil, nil)
+	})
+}
@@ -0,0 +1,91 @@
+package qstring
+
+import "reflect"
+
+// typeConverter converts the value of the registered type to and from the string
+type typeConverter struct {
+	encode func(rv reflect.Value) (string, error)
+	decode func(s string) (reflect.Value, error)
+}
+
+// RegisterType registers the functions to convert the type T to and from the string value.
+//
+// The functions are used before the built-in conversions wherever T appears:
+// the fields, the elements of slices and arrays, the values of maps and behind pointers.
+// It is useful for the types that can't have methods added, such as decimal types of other libraries.
+//
+//	enc := qstring.NewEncoder(qstring.RegisterType(formatDecimal, parseDecimal))
+//
+// Encoder ignores decode and Decoder ignores encode, either can be nil.
+// The error of decode is returned wrapped in FieldError.
+func RegisterType[T interface{}](encode func(T) (string, error), decode func(string) (T, error)) Option {
+	return func(c *config) {
+		if c.types == nil {
+			c.types = make(map[reflect.Type]typeConverter)
+		}
+
+		conv := typeConverter{}
+		if encode != nil {
+			conv.encode = func(rv reflect.Value) (string, error) {
+				return encode(rv.Interface().(T))
+			}
+		}
+		if decode != nil {
+			conv.decode = func(s string) (reflect.Value, error) {
+				v, err := decode(s)
+				return reflect.ValueOf(&v).Elem(), err
+			}
+		}
+		c.types[reflect.TypeOf((*T)(nil)).Elem()] = conv
+	}
+}
+
+// typeEncoder returns the registered encode function of the type
+func (c *config) typeEncoder(rt reflect.Type) (func(reflect.Value) (string, error), bool) {
+	conv, ok := c.types[rt]
+	return conv.encode, ok && conv.encode != nil
+}
+
+// typeDecoder returns the registered decode function of the type
+func (c *config) typeDecoder(rt reflect.Type) (func(string) (reflect.Value, error), bool) {
+	conv, ok := c.types[rt]
+	return conv.decode, ok && conv.decode != nil
+}
+
+// setRegistered decodes the value with the registered function of rv's type or rt.
+// The first return value reports whether the type is registered.
+func (d *decoder) setRegistered(rt reflect.Type, rv reflect.Value, uv urlValue) (bool, error) {
+	// the pointer type itself can be registered
+	if decode, ok := d.config.typeDecoder(rv.Type()); ok {
+		val, err := decodeRegistered(decode, rv.Type(), uv)
+		if err == nil {
+			rv.Set(val)
+		}
+		return true, err
+	}
+
+	decode, ok := d.config.typeDecoder(rt)
+	if !ok {
+		return false, nil
+	}
+
+	val, err := decodeRegistered(decode, rt, uv)
+	if err != nil {
+		return true, err
+	}
+	if d.isPtr(rv) {
+		ptr := reflect.New(rt)
+		ptr.Elem().Set(val)
+		rv.Set(ptr)
+	} else {
+		rv.Set(val)
+	}
+	return true, nil
+}
+
+func decodeRegistered(decode func(string) (reflect.Value, error), rt reflect.Type, uv urlValue) (reflect.Value, error) {
+	if !uv.hasSingleValue() {
+		return reflect.Value{}, &noAssignableValueError{rt, uv.String()}
+	}
+	return decode(uv.values[0])
+}
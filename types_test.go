@@ -0,0 +1,174 @@
+package qstring_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+// decimal is a third-party like type without the methods for qstring
+type decimal struct {
+	units int64
+	cents int64
+}
+
+func formatDecimal(d decimal) (string, error) {
+	return fmt.Sprintf("%d.%02d", d.units, d.cents), nil
+}
+
+func parseDecimal(s string) (decimal, error) {
+	units, cents, ok := strings.Cut(s, ".")
+	u, err := strconv.ParseInt(units, 10, 64)
+	if err != nil {
+		return decimal{}, errors.New("invalid decimal " + s)
+	}
+	var c int64
+	if ok {
+		if c, err = strconv.ParseInt(cents, 10, 64); err != nil {
+			return decimal{}, errors.New("invalid decimal " + s)
+		}
+	}
+	return decimal{units: u, cents: c}, nil
+}
+
+type status int32
+
+func formatStatus(s status) (string, error) {
+	switch s {
+	case 1:
+		return "active", nil
+	case 2:
+		return "closed", nil
+	}
+	return "", errors.New("unknown status " + strconv.Itoa(int(s)))
+}
+
+func parseStatus(s string) (status, error) {
+	switch s {
+	case "active":
+		return 1, nil
+	case "closed":
+		return 2, nil
+	}
+	return 0, errors.New("unknown status " + s)
+}
+
+func TestRegisterType(t *testing.T) {
+	type item struct {
+		Price decimal `qstring:"price"`
+	}
+	type s struct {
+		Price    decimal            `qstring:"price"`
+		PriceP   *decimal           `qstring:"price_p"`
+		Status   status             `qstring:"status"`
+		Statuses []status           `qstring:"statuses"`
+		Items    []item             `qstring:"items"`
+		Prices   map[string]decimal `qstring:"prices"`
+		Grid     [][]*decimal       `qstring:"grid"`
+		Any      interface{}        `qstring:"any"`
+	}
+
+	opts := []qstring.Option{
+		qstring.RegisterType(formatDecimal, parseDecimal),
+		qstring.RegisterType(formatStatus, parseStatus),
+	}
+	enc := qstring.NewEncoder(opts...)
+	dec := qstring.NewDecoder(opts...)
+
+	v := s{
+		Price:    decimal{1, 50},
+		PriceP:   &decimal{2, 5},
+		Status:   1,
+		Statuses: []status{2, 1},
+		Items:    []item{{Price: decimal{3, 0}}},
+		Prices:   map[string]decimal{"a": {4, 10}},
+		Grid:     [][]*decimal{{{5, 0}, {6, 1}}},
+		Any:      decimal{7, 0},
+	}
+
+	t.Run("encode", func(t *testing.T) {
+		actual, err := enc.Encode(v)
+		expected := "any=7.00&grid%5B0%5D%5B0%5D=5.00&grid%5B0%5D%5B1%5D=6.01&items%5B0%5D%5Bprice%5D=3.00" +
+			"&price=1.50&price_p=2.05&prices%5Ba%5D=4.10&status=active&statuses%5B0%5D=closed&statuses%5B1%5D=active"
+		assertResult(t, "Encode()", actual, err, expected, nil)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		q, err := enc.Encode(v)
+		if err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+
+		var actual s
+		err = dec.Decode(q, &actual)
+		// the interface field is decoded dynamically
+		expected := v
+		expected.Any = "7.00"
+		assertResult(t, "Decode()", actual, err, expected, nil)
+	})
+
+	testCases := []struct {
+		name     string
+		dec      *qstring.Decoder
+		q        string
+		expected s
+		err      error
+	}{
+		{name: "flat slice", dec: dec, q: "statuses[]=closed&statuses[]=active", expected: s{Statuses: []status{2, 1}}},
+		{name: "decode error", dec: dec, q: "price=x", err: fmt.Errorf("invalid decimal x")},
+		{name: "nested decode error", dec: dec, q: "items[0][price]=1.x", err: fmt.Errorf("invalid decimal 1.x")},
+		{name: "multiple values", dec: dec, q: "price=1&price=2", err: fmt.Errorf(`key "price" is given 2 times`)},
+		{name: "duplicate policy", dec: qstring.NewDecoder(append(opts, qstring.WithDuplicatePolicy(qstring.DuplicateLast, ""))...),
+			q: "price=1&price=2", expected: s{Price: decimal{2, 0}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual s
+			err := tc.dec.Decode(tc.q, &actual)
+			assertResult(t, "Decode()", actual, err, tc.expected, tc.err)
+		})
+	}
+
+	t.Run("encode error", func(t *testing.T) {
+		_, err := enc.Encode(s{Status: 9})
+		assertResult(t, "Encode()", nil, err, nil, fmt.Errorf("unknown status 9"))
+	})
+
+	t.Run("pointer type", func(t *testing.T) {
+		type p struct {
+			Price *decimal `qstring:"price"`
+		}
+		opt := qstring.RegisterType(func(d *decimal) (string, error) {
+			if d == nil {
+				return "none", nil
+			}
+			return formatDecimal(*d)
+		}, func(s string) (*decimal, error) {
+			if s == "none" {
+				return nil, nil
+			}
+			d, err := parseDecimal(s)
+			return &d, err
+		})
+
+		actual, err := qstring.NewEncoder(opt).Encode(p{})
+		assertResult(t, "Encode()", actual, err, "price=none", nil)
+
+		var decoded p
+		err = qstring.NewDecoder(opt).Decode("price=1.5", &decoded)
+		assertResult(t, "Decode()", decoded, err, p{Price: &decimal{1, 5}}, nil)
+	})
+
+	t.Run("field error", func(t *testing.T) {
+		err := dec.Decode("items[0][price]=x", &s{})
+		var fe *qstring.FieldError
+		if !errors.As(err, &fe) || fe.Key != "items[0][price]" {
+			t.Errorf("Decode() error = %#v, want the key items[0][price]", err)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package qstring
 
 import (
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -22,16 +23,45 @@ func (vm urlValueMap) sortedChild() []urlValue {
 		uvs = append(uvs, uv)
 	}
 	sort.Slice(uvs, func(i, j int) bool {
-		return uvs[i].key < uvs[j].key
+		return lessKey(uvs[i].key, uvs[j].key)
+	})
+	return uvs
+}
+
+// lessKey compares the keys as numbers if both are array indexes
+func lessKey(a, b string) bool {
+	ai, aerr := strconv.Atoi(a)
+	bi, berr := strconv.Atoi(b)
+	if aerr == nil && berr == nil {
+		return ai < bi
+	}
+	return a < b
+}
+
+// orderedChild returns the values in the order they appeared in the query string
+func (vm urlValueMap) orderedChild() []urlValue {
+	uvs := make([]urlValue, 0, len(vm))
+	for _, uv := range vm {
+		uvs = append(uvs, uv)
+	}
+	sort.Slice(uvs, func(i, j int) bool {
+		return uvs[i].order < uvs[j].order
 	})
 	return uvs
 }
 
 type urlValue struct {
-	key      string
+	key string
+	// path is the key of the query string in bracket form (e.g. "key[a][0]")
+	path     string
 	values   []string
 	isString bool
 	child    urlValueMap
+	// indexed is the elements of the simple array by index,
+	// it is nil if the keys are not all indexes (e.g. "key[]")
+	indexed urlValueMap
+	// order is the position in the query string where the key first appeared
+	order int
 }
 
 func (uv urlValue) hasChild() bool {
@@ -42,6 +72,49 @@ func (uv urlValue) hasSingleValue() bool {
 	return len(uv.values) == 1 && !uv.hasChild()
 }
 
+// arrayElem is the element of the array value and its index
+type arrayElem struct {
+	index int
+	value urlValue
+}
+
+// elements returns the elements of the array value.
+// The elements are placed at their indexes if all the keys are indexes,
+// otherwise they are numbered in order.
+func (uv urlValue) elements() []arrayElem {
+	children := uv.child
+	if !uv.hasChild() {
+		children = uv.indexed
+	}
+
+	if children == nil {
+		elems := make([]arrayElem, 0, len(uv.values))
+		for i, v := range uv.values {
+			elems = append(elems, arrayElem{i, urlValue{key: strconv.Itoa(i), values: []string{v}, isString: true}})
+		}
+		return elems
+	}
+
+	sorted := children.sortedChild()
+	elems := make([]arrayElem, 0, len(sorted))
+	for _, cuv := range sorted {
+		idx, err := strconv.Atoi(cuv.key)
+		if err != nil || idx < 0 {
+			break
+		}
+		elems = append(elems, arrayElem{idx, cuv})
+	}
+	if len(elems) == len(sorted) {
+		return elems
+	}
+
+	elems = elems[:0]
+	for i, cuv := range sorted {
+		elems = append(elems, arrayElem{i, cuv})
+	}
+	return elems
+}
+
 func (uv urlValue) String() string {
 	if len(uv.values) == 0 {
 		return ""
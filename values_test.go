@@ -0,0 +1,136 @@
+package qstring_test
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/masakurapa/qstring"
+)
+
+func TestFromValues(t *testing.T) {
+	values := url.Values{
+		"page":            {"1"},
+		"tags[]":          {"a", "b"},
+		"filter[name]":    {"x"},
+		"items[0][price]": {"100"},
+		"items[1][price]": {"200"},
+		"dup":             {"1", "2"},
+	}
+	expected := qstring.Q{
+		"page":   "1",
+		"tags":   []string{"a", "b"},
+		"filter": qstring.Q{"name": "x"},
+		"items":  qstring.S{qstring.Q{"price": "100"}, qstring.Q{"price": "200"}},
+		"dup":    []string{"1", "2"},
+	}
+
+	actual, err := qstring.FromValues(values)
+	if err != nil {
+		t.Fatalf("FromValues() should not returns error, got %q", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("FromValues() returns \n%#v\nwant \n%#v", actual, expected)
+	}
+
+	actual, err = qstring.FromValues(nil)
+	if err != nil {
+		t.Fatalf("FromValues() should not returns error, got %q", err)
+	}
+	if !reflect.DeepEqual(actual, qstring.Q{}) {
+		t.Errorf("FromValues() returns %#v, want %#v", actual, qstring.Q{})
+	}
+}
+
+func TestQ_Values(t *testing.T) {
+	q := qstring.Q{
+		"page":   "1",
+		"filter": qstring.Q{"name": "x"},
+		"items":  qstring.S{qstring.Q{"price": "100"}},
+		"tags":   []string{"a", "b"},
+		"ch":     make(chan int),
+	}
+	expected := url.Values{
+		"page":            {"1"},
+		"filter[name]":    {"x"},
+		"items[0][price]": {"100"},
+		"tags[0]":         {"a"},
+		"tags[1]":         {"b"},
+	}
+
+	if actual := q.Values(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Values() returns \n%#v\nwant \n%#v", actual, expected)
+	}
+}
+
+func TestDecodeValues(t *testing.T) {
+	type item struct {
+		Price int `qstring:"price"`
+	}
+	type s struct {
+		Page  int      `qstring:"page"`
+		Items []item   `qstring:"items"`
+		Tags  []string `qstring:"tags"`
+	}
+
+	values := url.Values{
+		"page":            {"2"},
+		"items[0][price]": {"100"},
+		"items[1][price]": {"200"},
+		"tags[]":          {"a", "b"},
+	}
+
+	v := s{}
+	if err := qstring.DecodeValues(values, &v); err != nil {
+		t.Fatalf("DecodeValues() should not returns error, got %q", err)
+	}
+	expected := s{Page: 2, Items: []item{{Price: 100}, {Price: 200}}, Tags: []string{"a", "b"}}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("DecodeValues() returns \n%#v\nwant \n%#v", v, expected)
+	}
+
+	err := qstring.DecodeValues(url.Values{"page": {"a"}}, &v)
+	if err == nil || err.Error() != `"a" can not be assign to int` {
+		t.Errorf("DecodeValues() error returns %v, want %q", err, `"a" can not be assign to int`)
+	}
+
+	str := ""
+	if err := qstring.DecodeValues(url.Values{"a[b]": {"1 2"}}, &str); err != nil {
+		t.Fatalf("DecodeValues() should not returns error, got %q", err)
+	}
+	if str != "a[b]=1 2" {
+		t.Errorf("DecodeValues() returns %q, want %q", str, "a[b]=1 2")
+	}
+}
+
+func TestEncodeValues(t *testing.T) {
+	type item struct {
+		Price int `qstring:"price"`
+	}
+	type s struct {
+		Page  int    `qstring:"page"`
+		Items []item `qstring:"items"`
+		Sort  string `qstring:"sort,omitempty"`
+	}
+
+	testCases := []struct {
+		name     string
+		v        interface{}
+		err      error
+		expected url.Values
+	}{
+		{name: "struct", v: s{Page: 1, Items: []item{{Price: 100}}}, expected: url.Values{"page": {"1"}, "items[0][price]": {"100"}}},
+		{name: "map", v: qstring.Q{"a": qstring.Q{"b": "1"}}, expected: url.Values{"a[b]": {"1"}}},
+		{name: "string", v: "?a[b]=1&c=2&c=3&d", expected: url.Values{"a[b]": {"1"}, "c": {"2", "3"}, "d": {""}}},
+		{name: "nil", v: nil, err: fmt.Errorf("nil is not supported")},
+		{name: "unsupported", v: 1, err: fmt.Errorf("int is not supported")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := qstring.EncodeValues(tc.v)
+			assertResult(t, "EncodeValues()", actual, err, tc.expected, tc.err)
+		})
+	}
+}